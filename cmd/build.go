@@ -5,16 +5,15 @@ import (
 	"log"
 	"time"
 
-	"github.com/euforicio/adr-demo/internal/config"
 	"github.com/euforicio/adr-demo/internal/generator"
 	"github.com/spf13/cobra"
 )
 
 var (
-	configFile string
-	outputDir  string
-	baseURL    string
-	minify     bool
+	outputDir string
+	baseURL   string
+	minify    bool
+	gitMeta   bool
 )
 
 // buildCmd represents the build command
@@ -30,29 +29,21 @@ The generated site includes:
 • Optimized assets and SEO meta tags
 • Mermaid diagrams rendered as SVG
 
-Output is generated in the docs/ directory by default, ready for GitHub Pages deployment.`,
+Output is generated in the docs/ directory by default, ready for GitHub Pages deployment.
+
+Settings are layered: built-in defaults, $HOME/.adr-gen.yaml, adr-config.yaml,
+ADR_GEN_* environment variables (e.g. ADR_GEN_OUTPUT_DIRECTORY, ADR_GEN_BASE_URL)
+and finally command-line flags.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		start := time.Now()
 
-		// Load configuration
-		cfg, err := config.LoadConfig(configFile)
-		if err != nil {
-			log.Fatalf("Failed to load configuration: %v", err)
-		}
-
-		// Override config with command line flags if provided
-		if outputDir != "" {
-			cfg.OutputDirectory = outputDir
-		}
-		if baseURL != "" {
-			cfg.BaseURL = baseURL
-		}
-		if minify {
-			cfg.Minify = minify
-		}
-		if verbose {
-			cfg.Verbose = verbose
-		}
+		// Load configuration; flags take precedence over files and environment
+		cfg := loadConfig(cmd, map[string]string{
+			"output":       "output_directory",
+			"base-url":     "base_url",
+			"minify":       "minify",
+			"git-metadata": "git_metadata",
+		})
 
 		if cfg.Verbose {
 			fmt.Printf("🔧 Building static site...\n")
@@ -92,8 +83,8 @@ Output is generated in the docs/ directory by default, ready for GitHub Pages de
 func init() {
 	rootCmd.AddCommand(buildCmd)
 
-	buildCmd.Flags().StringVarP(&configFile, "config", "c", "", "config file path (default: look for adr-config.yaml)")
 	buildCmd.Flags().StringVarP(&outputDir, "output", "o", "", "output directory for generated site (overrides config)")
 	buildCmd.Flags().StringVar(&baseURL, "base-url", "", "base URL for the site (overrides config)")
 	buildCmd.Flags().BoolVar(&minify, "minify", false, "minify HTML, CSS, and JavaScript (overrides config)")
+	buildCmd.Flags().BoolVar(&gitMeta, "git-metadata", false, "derive ADR dates and authors from git history (overrides config)")
 }
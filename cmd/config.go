@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+// configCmd groups the configuration subcommands
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and validate the configuration",
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate configuration files against the schema",
+	Long: `Validate configuration files against the adr-gen schema.
+
+Checks:
+• Unknown keys, such as misspellings of allowed_statuses
+• Values of the wrong type (lists, mappings, true/false)
+• Status colors (Tailwind color names or hex values)
+• Status CSS classes
+• Rule IDs, severities and parameters in the rules section
+• The merged configuration, e.g. that the ADR directory exists
+
+Without a file, the global $HOME/.adr-gen.yaml and the repository
+adr-config.yaml are checked. Every problem is reported with its line and
+column number.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cfgFile
+		var files []string
+		if len(args) == 1 {
+			path = args[0]
+			files = args
+		} else {
+			found, err := config.Files(path)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				fmt.Printf("❌ Configuration is invalid\n")
+				os.Exit(1)
+			}
+			files = found
+		}
+		if len(files) == 0 {
+			fmt.Printf("💡 No configuration file found; built-in defaults are used\n")
+		}
+
+		failed := false
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				failed = true
+				continue
+			}
+
+			errs := config.CheckSchema(file, data)
+			for _, err := range errs {
+				fmt.Printf("❌ %s\n", err)
+			}
+			if len(errs) > 0 {
+				failed = true
+			} else if verbose {
+				fmt.Printf("✅ %s matches the schema\n", file)
+			}
+		}
+
+		// Check the merged result, including environment variables
+		if !failed {
+			cfg, err := config.LoadConfig(path, nil)
+			if err != nil {
+				var schemaErrs config.SchemaErrors
+				if !errors.As(err, &schemaErrs) {
+					fmt.Printf("❌ %v\n", err)
+				}
+				failed = true
+			} else if err := validator.CheckRules(cfg); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				failed = true
+			}
+		}
+
+		if failed {
+			fmt.Printf("❌ Configuration is invalid\n")
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Configuration is valid\n")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var linkType string
+
+// linkCmd represents the link command
+var linkCmd = &cobra.Command{
+	Use:   "link <a> <b>",
+	Short: "Add a typed link between two ADRs",
+	Long: `Record how two ADRs relate, in both documents.
+
+The link is added as a list entry under "Related Decisions" in each ADR,
+creating the section at the end when missing, and the second ADR gets the
+matching entry back:
+
+  --type amends          A amends B       B is amended by A
+  --type depends-on      A depends on B   B is depended on by A
+  --type relates-to      A relates to B   B relates to A
+  --type conflicts-with  A conflicts with B and B with A
+
+Entries look like "- Depends on [ADR-0003: Title](0003-title.md)". The site
+shows them in a Related decisions panel on each ADR page, and depends-on
+links count for the deprecated-dependency rule of "adr-gen validate".
+Replacing a decision is done with "adr-gen supersede" instead.
+
+Examples:
+  adr-gen link 7 3 --type depends-on
+  adr-gen link 12 5 --type amends`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		from := parseADRNumber(args[0])
+		to := parseADRNumber(args[1])
+
+		cfg := loadConfig(cmd, nil)
+
+		result, err := generator.Link(cfg, from, to, generator.RelationType(linkType))
+		if err != nil {
+			log.Fatalf("Failed to link ADRs: %v", err)
+		}
+
+		fmt.Printf("✅ ADR-%s %s ADR-%s\n", result.From.Number, strings.ToLower(result.Type.Label()), result.To.Number)
+		if verbose {
+			for _, path := range result.Added {
+				fmt.Printf("   Updated: %s\n", path)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linkCmd)
+
+	linkCmd.Flags().StringVarP(&linkType, "type", "t", string(generator.RelationRelatesTo), "link type: amends, depends-on, relates-to or conflicts-with")
+}
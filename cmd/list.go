@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/euforicio/adr-demo/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listStatuses   []string
+	listCategories []string
+	listFrom       string
+	listTo         string
+	listText       string
+	listSort       string
+	listReverse    bool
+	listFormat     string
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List and filter ADRs",
+	Long: `List the ADRs of the decision log without building the site.
+
+Filters combine: an ADR is listed when it matches every filter given.
+--status and --category accept several comma-separated values and ignore
+case; categories also match their folder names (e.g. "security"). --from
+and --to take YYYY-MM-DD dates and include both ends; an ADR's date comes
+from its front matter, git history (git_metadata) or file time. --text
+searches titles and content.
+
+Use --format to choose the output:
+  table     Aligned columns for the terminal (default)
+  json      Array of ADRs with number, title, status, category, date, author,
+            tags and file
+  csv       Spreadsheet-friendly rows with a header
+  markdown  GitHub Flavored Markdown table linking each ADR
+
+Examples:
+  adr-gen list --status proposed --category security
+  adr-gen list --from 2024-01-01 --sort date --reverse
+  adr-gen list --text kafka --format json
+  adr-gen list --status accepted --format markdown > DECISIONS.md`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !containsValue(generator.ListFormats, listFormat) {
+			log.Fatalf("Unknown format %q (valid: %s)", listFormat, strings.Join(generator.ListFormats, ", "))
+		}
+		if !containsValue(generator.SortKeys, listSort) {
+			log.Fatalf("Unknown sort key %q (valid: %s)", listSort, strings.Join(generator.SortKeys, ", "))
+		}
+
+		query := generator.Query{
+			Statuses:   listStatuses,
+			Categories: listCategories,
+			From:       parseListDate("from", listFrom),
+			To:         parseListDate("to", listTo),
+			Text:       listText,
+			Sort:       listSort,
+			Reverse:    listReverse,
+		}
+		if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+			log.Fatalf("--to %s is before --from %s", listTo, listFrom)
+		}
+
+		cfg := loadConfig(cmd, nil)
+
+		gen := generator.New(cfg)
+		if err := gen.LoadADRsOnly(); err != nil {
+			log.Fatalf("Failed to load ADRs: %v", err)
+		}
+
+		adrs := generator.Filter(gen.GetADRs(), query)
+		if err := generator.WriteListing(os.Stdout, listFormat, adrs); err != nil {
+			log.Fatalf("Failed to write listing: %v", err)
+		}
+		if listFormat == generator.ListTable {
+			fmt.Fprintf(os.Stderr, "📋 %d of %d ADRs\n", len(adrs), len(gen.GetADRs()))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringSliceVar(&listStatuses, "status", nil, "only ADRs with these statuses")
+	listCmd.Flags().StringSliceVar(&listCategories, "category", nil, "only ADRs in these categories")
+	listCmd.Flags().StringVar(&listFrom, "from", "", "only ADRs dated on or after YYYY-MM-DD")
+	listCmd.Flags().StringVar(&listTo, "to", "", "only ADRs dated on or before YYYY-MM-DD")
+	listCmd.Flags().StringVarP(&listText, "text", "t", "", "only ADRs whose title or content contains this text")
+	listCmd.Flags().StringVar(&listSort, "sort", generator.SortNumber, "sort by: "+strings.Join(generator.SortKeys, ", "))
+	listCmd.Flags().BoolVarP(&listReverse, "reverse", "r", false, "reverse the sort order")
+	listCmd.Flags().StringVarP(&listFormat, "format", "f", generator.ListTable, "output format: "+strings.Join(generator.ListFormats, ", "))
+}
+
+// parseListDate parses a YYYY-MM-DD flag value; empty means no bound
+func parseListDate(flag, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		log.Fatalf("Invalid --%s date %q: expected YYYY-MM-DD", flag, value)
+	}
+	return date
+}
+
+// containsValue reports whether list includes value
+func containsValue(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
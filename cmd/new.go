@@ -3,15 +3,20 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"os"
 
+	"github.com/euforicio/adr-demo/internal/config"
 	"github.com/euforicio/adr-demo/internal/generator"
+	"github.com/euforicio/adr-demo/internal/gitmeta"
 	"github.com/spf13/cobra"
 )
 
 var (
-	status   string
-	category string
-	force    bool
+	status    string
+	category  string
+	author    string
+	adrFormat string
+	force     bool
 )
 
 // newCmd represents the new command
@@ -22,15 +27,28 @@ var newCmd = &cobra.Command{
 
 The new ADR will:
 • Use the next available number (e.g., 0011-title.md)
-• Be pre-filled with the standard ADR template
+• Be pre-filled from template.md in the ADR directory, or from the format
+  selected with --format (default_format in adr-config.yaml otherwise)
 • Have the title automatically formatted in kebab-case
-• Be created in the adr/ directory
+• Be created in the configured ADR directory, or in <adr_directory>/<category>/
+  when category_folders is enabled in adr-config.yaml
+
+The template may use these variables: {{number}}, {{title}}, {{status}},
+{{date}}, {{category}} and {{author}}.
+
+Built-in formats:
+  nygard       Status / Context / Decision / Consequences
+  madr         Markdown Architectural Decision Records (MADR 3)
+  y-statement  A single "In the context of ..., facing ..." sentence
+
+Custom formats can be declared under "formats" in adr-config.yaml.
 
 Examples:
   adr-gen new "Use Redis for Caching"
   adr-gen new "Implement API Gateway" --status accepted
-  adr-gen new "Frontend Framework Choice" --category frontend
-  adr-gen new "Security Policy" --category security --status accepted`,
+  adr-gen new "Frontend Framework Choice" --category "Frontend Development"
+  adr-gen new "Security Policy" --category security --status accepted
+  adr-gen new "Choose a Message Broker" --format madr`,
 	Args: cobra.RangeArgs(1, 10), // Allow multiple words for title
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
@@ -55,12 +73,26 @@ Examples:
 			}
 		}
 
+		// The first ADR of a project creates the ADR directory
+		cfg := loadConfigWith(cmd, nil, config.LoadConfigForCreate)
+
+		// Default the author to the git identity, then the login name
+		if author == "" {
+			author = gitmeta.UserName(".")
+		}
+		if author == "" {
+			author = os.Getenv("USER")
+		}
+
 		creator := generator.NewADRCreator(&generator.ADRConfig{
 			Title:    title,
 			Status:   status,
 			Category: category,
+			Author:   author,
+			Format:   adrFormat,
 			Force:    force,
 			Verbose:  verbose,
+			Project:  cfg,
 		})
 
 		filename, err := creator.Create()
@@ -84,7 +116,9 @@ Examples:
 func init() {
 	rootCmd.AddCommand(newCmd)
 
-	newCmd.Flags().StringVarP(&status, "status", "s", "Proposed", "initial status for the ADR (Proposed, Accepted, Deprecated, Superseded)")
-	newCmd.Flags().StringVarP(&category, "category", "c", "", "category/folder for the ADR (e.g., frontend, infrastructure, security)")
+	newCmd.Flags().StringVarP(&status, "status", "s", "Proposed", "initial status for the ADR, one of allowed_statuses in adr-config.yaml (case-insensitive)")
+	newCmd.Flags().StringVarP(&category, "category", "c", "", "category for the ADR, one of allowed_categories (e.g., security, frontend-development)")
+	newCmd.Flags().StringVarP(&author, "author", "a", "", "author recorded in the ADR (default: git user.name)")
+	newCmd.Flags().StringVar(&adrFormat, "format", "", "ADR format: nygard, madr, y-statement or a custom format")
 	newCmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite existing ADR if it exists")
 }
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renumberCompact bool
+	renumberDryRun  bool
+)
+
+// renumberCmd represents the renumber command
+var renumberCmd = &cobra.Command{
+	Use:   "renumber [<from> <to>]",
+	Short: "Give ADRs new numbers and rewrite references to them",
+	Long: `Move an ADR to a new number, or close every gap with --compact.
+
+<from> is an ADR number or the path of an ADR file. When two files share a
+number, for example after two branches both added 0012-*.md, the newer one
+by git history moves unless a path is given. --compact numbers the whole
+log without gaps from its lowest number; older files keep lower numbers.
+
+Renumbering:
+• Renames the files, keeping their slugs and folders
+• Rewrites markdown links to the moved files and ADR-NNNN mentions in
+  every ADR, including supersedes, superseded_by and depends_on in front
+  matter; code blocks are left alone
+• Records old numbers in <adr_directory>/redirects.yaml, so the built site
+  keeps adr-NNNN.html as a redirect and "adr-gen serve" answers with a
+  permanent redirect
+• Reports old numbers that another ADR takes over, and earlier redirects
+  dropped because their number belongs to an ADR again; links to those
+  numbers from outside the log now reach a different decision
+
+Mentions of a number that several files share cannot be told apart; they
+are left as they are (except inside the moved file) and reported.
+
+Examples:
+  adr-gen renumber 12 13
+  adr-gen renumber adr/0012-use-grpc.md 13
+  adr-gen renumber --compact --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch {
+		case renumberCompact && len(args) != 0:
+			log.Fatal("--compact renumbers the whole log; do not pass <from> <to>")
+		case !renumberCompact && len(args) != 2:
+			log.Fatal("Please pass <from> <to>, or --compact")
+		}
+
+		cfg := loadConfig(cmd, nil)
+		opts := generator.RenumberOptions{DryRun: renumberDryRun}
+
+		var result *generator.Renumbering
+		var err error
+		if renumberCompact {
+			result, err = generator.Compact(cfg, opts)
+		} else {
+			result, err = generator.Renumber(cfg, args[0], parseADRNumber(args[1]), opts)
+		}
+		if err != nil {
+			log.Fatalf("Failed to renumber: %v", err)
+		}
+
+		if len(result.Moves) == 0 {
+			fmt.Println("✅ ADR numbers already run without gaps")
+			return
+		}
+
+		verb := "Moved"
+		if renumberDryRun {
+			verb = "Would move"
+		}
+		for _, move := range result.Moves {
+			fmt.Printf("✅ %s ADR-%s → ADR-%s: %s\n", verb, move.OldNumber, move.NewNumber, filepath.Base(move.To))
+		}
+		if verbose || renumberDryRun {
+			for _, path := range result.Updated {
+				fmt.Printf("   References: %s\n", path)
+			}
+		}
+		fmt.Printf("📝 References rewritten in %s\n", plural(len(result.Updated), "ADR", "ADRs"))
+		if len(result.Redirects) > 0 {
+			fmt.Printf("↪️  %s in the built site (%s)\n", plural(len(result.Redirects), "old number redirects", "old numbers redirect"), generator.RedirectsFile)
+		}
+		if len(result.Reassigned) > 0 {
+			fmt.Printf("⚠️  %s now used by another ADR (%s); links to them from outside the log reach a different decision\n",
+				plural(len(result.Reassigned), "old number is", "old numbers are"), "ADR-"+strings.Join(result.Reassigned, ", ADR-"))
+		}
+		dropped := make([]string, 0, len(result.Dropped))
+		for number := range result.Dropped {
+			dropped = append(dropped, number)
+		}
+		sort.Strings(dropped)
+		for _, number := range dropped {
+			fmt.Printf("⚠️  Dropped the redirect ADR-%s → ADR-%s, since ADR-%s belongs to an ADR again\n", number, result.Dropped[number], number)
+		}
+		for _, number := range result.Ambiguous {
+			fmt.Printf("💡 ADR-%s mentions outside the moved file were left as they are, since several files used that number; check them by hand\n", number)
+		}
+		if renumberDryRun {
+			fmt.Println("💡 Dry run: nothing was written")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renumberCmd)
+
+	renumberCmd.Flags().BoolVar(&renumberCompact, "compact", false, "renumber the whole log without gaps")
+	renumberCmd.Flags().BoolVar(&renumberDryRun, "dry-run", false, "show the changes without writing them")
+}
+
+// plural formats n with the singular or plural phrase
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 
+	"github.com/euforicio/adr-demo/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -40,7 +42,7 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.adr-gen.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: adr-config.yaml, layered over $HOME/.adr-gen.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 
 	// Version flag
@@ -53,3 +55,32 @@ func initConfig() {
 		fmt.Println("adr-gen starting...")
 	}
 }
+
+// loadConfig loads the layered configuration for cmd. flagKeys maps the
+// command's flags to configuration keys; only flags set on the command line
+// override the files and ADR_GEN_* environment variables.
+func loadConfig(cmd *cobra.Command, flagKeys map[string]string) *config.Config {
+	return loadConfigWith(cmd, flagKeys, config.LoadConfig)
+}
+
+// loadConfigWith collects the overrides of cmd and loads the configuration
+// with load
+func loadConfigWith(cmd *cobra.Command, flagKeys map[string]string, load func(string, map[string]string) (*config.Config, error)) *config.Config {
+	overrides := make(map[string]string)
+	for flag, key := range flagKeys {
+		if f := cmd.Flags().Lookup(flag); f != nil && f.Changed {
+			overrides[key] = f.Value.String()
+		}
+	}
+	if cmd.Flags().Changed("verbose") {
+		overrides["verbose"] = fmt.Sprint(verbose)
+	}
+
+	cfg, err := load(cfgFile, overrides)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	verbose = cfg.Verbose
+	return cfg
+}
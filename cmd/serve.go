@@ -30,19 +30,21 @@ Features:
 
 Perfect for previewing ADRs during development - just edit and refresh!`,
 	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig(cmd, nil)
+
 		if verbose {
 			fmt.Printf("🚀 Starting development server...\n")
 			fmt.Printf("   Host: %s\n", host)
 			fmt.Printf("   Port: %d\n", port)
+			fmt.Printf("   ADR Directory: %s\n", cfg.ADRDirectory)
 		}
 
-		config := &server.Config{
+		srv := server.New(&server.Config{
 			Host:    host,
 			Port:    port,
 			Verbose: verbose,
-		}
-
-		srv := server.New(config)
+			Project: cfg,
+		})
 
 		// Auto-open browser if requested
 		if open {
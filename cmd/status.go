@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/euforicio/adr-demo/internal/generator"
+	"github.com/euforicio/adr-demo/internal/gitmeta"
+	"github.com/spf13/cobra"
+)
+
+var statusReason string
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status <number> <new-status>",
+	Short: "Change the status of an ADR",
+	Long: `Move an ADR to a new status.
+
+The move must be allowed by status_transitions in adr-config.yaml, which
+lists the statuses each status may move to (e.g. Proposed → Accepted, but
+never Deprecated → Proposed). Statuses missing from the graph may move
+anywhere.
+
+The command:
+• Rewrites the status in the front matter and under ## Status, keeping any
+  text after the status word
+• Appends a dated entry with your git identity (and --reason) to the
+  ## Status History section, creating it after ## Status when missing
+• When the ADR becomes Accepted, moves the ADRs it supersedes to
+  Superseded with a "Superseded by" link and the same --reason, as
+  "adr-gen supersede" prepares
+
+"adr-gen validate" enforces the same graph against git history.
+
+Examples:
+  adr-gen status 12 accepted
+  adr-gen status 7 deprecated --reason "Replaced by the managed service"`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		number := parseADRNumber(args[0])
+
+		cfg := loadConfig(cmd, nil)
+
+		change, err := generator.ChangeStatus(cfg, generator.StatusUpdate{
+			Number: number,
+			Status: args[1],
+			Author: gitmeta.UserName("."),
+			Reason: statusReason,
+			Date:   time.Now(),
+		})
+		if err != nil {
+			log.Fatalf("Failed to change status: %v", err)
+		}
+
+		fmt.Printf("✅ ADR-%04d: %s → %s\n", number, change.From, change.To)
+		if verbose {
+			fmt.Printf("   File: %s\n", change.Path)
+		}
+		for _, old := range change.Superseded {
+			fmt.Printf("✅ %s: %s → %s\n", filepath.Base(old.Path), old.From, old.To)
+		}
+		switch strings.ToLower(change.To) {
+		case "superseded":
+			fmt.Println("💡 Link the replacement on the status line, e.g. \"Superseded by ADR-NNNN\"")
+		case "deprecated":
+			if statusReason == "" {
+				fmt.Println("💡 Explain why the decision no longer applies in the ADR")
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringVar(&statusReason, "reason", "", "reason recorded in the status history")
+}
+
+// parseADRNumber parses an ADR number argument such as "7", "0007" or
+// "ADR-0007"
+func parseADRNumber(arg string) int {
+	number, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(arg), "ADR-"))
+	if err != nil || number <= 0 {
+		log.Fatalf("Invalid ADR number %q", arg)
+	}
+	return number
+}
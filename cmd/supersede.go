@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/euforicio/adr-demo/internal/generator"
+	"github.com/euforicio/adr-demo/internal/gitmeta"
+	"github.com/spf13/cobra"
+)
+
+var (
+	supersedeStatus   string
+	supersedeCategory string
+	supersedeFormat   string
+	supersedeReason   string
+)
+
+// supersedeCmd represents the supersede command
+var supersedeCmd = &cobra.Command{
+	Use:   "supersede <number> <new title>",
+	Short: "Replace an ADR with a new one",
+	Long: `Create a new ADR that supersedes an existing one, in one step.
+
+The command creates the new ADR like "adr-gen new", with a "Supersedes"
+link to the old ADR under its status and the old ADR's category unless
+--category is given. The new ADR starts as Proposed and the old ADR is
+not touched yet; write the new one up, then accept it with
+"adr-gen status <number> accepted [--reason ...]". Accepting it:
+• Moves the old ADR to Superseded
+• Adds a "Superseded by" link under the old ADR's status and records the
+  change, with the reason, in its ## Status History
+
+With --status accepted the old ADR is superseded right away, and --reason
+is recorded at once. --reason is rejected for a Proposed replacement;
+give it when accepting instead. Both files are
+prepared before anything is written, and the new ADR is removed again if
+the old one cannot be updated. The old ADR must be in a status that
+status_transitions in adr-config.yaml allows to move to Superseded.
+
+Examples:
+  adr-gen supersede 9 "Adopt Hybrid Session Storage"
+  adr-gen supersede 7 "Use gRPC for Internal APIs" --status accepted --reason "GraphQL federation was dropped"`,
+	Args: cobra.RangeArgs(2, 11), // Allow multiple words for title
+	Run: func(cmd *cobra.Command, args []string) {
+		number := parseADRNumber(args[0])
+		title := strings.Join(args[1:], " ")
+
+		cfg := loadConfig(cmd, nil)
+
+		// Default the author to the git identity, then the login name
+		author := gitmeta.UserName(".")
+		if author == "" {
+			author = os.Getenv("USER")
+		}
+
+		creator := generator.NewADRCreator(&generator.ADRConfig{
+			Title:    title,
+			Status:   supersedeStatus,
+			Category: supersedeCategory,
+			Author:   author,
+			Format:   supersedeFormat,
+			Verbose:  verbose,
+			Project:  cfg,
+		})
+
+		result, err := creator.Supersede(number, author, supersedeReason, time.Now())
+		if err != nil {
+			log.Fatalf("Failed to supersede ADR: %v", err)
+		}
+
+		fmt.Printf("✅ Created new ADR: %s\n", result.NewPath)
+		if result.Old != nil {
+			fmt.Printf("✅ ADR-%04d: %s → %s\n", number, result.Old.From, result.Old.To)
+			fmt.Printf("💡 Edit the new ADR to explain what changed and why\n")
+			return
+		}
+		fmt.Printf("💡 Edit the new ADR to explain what changed and why, then run \"adr-gen status %s accepted\"; ADR-%04d becomes Superseded then\n", result.NewNumber, number)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(supersedeCmd)
+
+	supersedeCmd.Flags().StringVarP(&supersedeStatus, "status", "s", "Proposed", "initial status for the new ADR")
+	supersedeCmd.Flags().StringVarP(&supersedeCategory, "category", "c", "", "category/folder for the new ADR (default: the old ADR's category)")
+	supersedeCmd.Flags().StringVar(&supersedeFormat, "format", "", "ADR format: nygard, madr, y-statement or a custom format")
+	supersedeCmd.Flags().StringVar(&supersedeReason, "reason", "", "reason recorded in the old ADR's status history (requires a --status other than Proposed)")
+}
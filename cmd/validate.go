@@ -1,91 +1,279 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"sort"
+	"strings"
 
+	"github.com/euforicio/adr-demo/internal/adrformat"
+	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/gitmeta"
 	"github.com/euforicio/adr-demo/internal/validator"
 	"github.com/spf13/cobra"
 )
 
 var (
-	strict bool
-	fix    bool
+	strict         bool
+	fix            bool
+	dryRun         bool
+	reportFormat   string
+	listRules      bool
+	since          string
+	maxWarnings    int
+	baselineFile   string
+	updateBaseline bool
 )
 
 // validateCmd represents the validate command
 var validateCmd = &cobra.Command{
-	Use:   "validate",
+	Use:   "validate [paths...]",
 	Short: "Validate ADR structure and content",
-	Long: `Validate all ADR files for:
+	Long: `Validate ADR files for:
 
 Structure:
 • Correct filename format (NNNN-kebab-case-title.md)
-• Sequential numbering without gaps
+• Filename slugs that match the # Title
+• Unique, sequential numbering without gaps
 • Required sections (Status, Context, Decision, Consequences)
 
+Duplicate numbers, such as two branches each adding 0012-*.md, are
+reported on the newer file with a suggested renumber; slug mismatches come
+with a suggested rename.
+
 Content:
 • Valid front matter (if present)
 • Proper heading hierarchy
-• Valid Mermaid diagram syntax
-• Working internal links
-• Consistent status values
+• Valid Mermaid diagram syntax (flowchart, sequenceDiagram, stateDiagram and
+  C4 diagrams: header, brackets and quotes, arrows, undefined nodes)
+• Working internal links, #anchors and relative image paths
+• Status and category values allowed by the configuration
+• No unfilled template placeholders (errors for Accepted ADRs, warnings
+  otherwise)
+• Glossary terms instead of their banned variants (--fix replaces them
+  outside headings and code)
+
+Across ADRs:
+• Superseded ADRs link to an existing replacement that is not Proposed
+• Superseding and superseded ADRs reference each other
+• Deprecated ADRs explain why
+• Accepted ADRs do not depend on Deprecated ones
+• Status changes since HEAD (or --since) follow status_transitions
+
+Every check is a rule with a stable ID. The rules section of
+adr-config.yaml enables or disables rules, sets their severity and passes
+parameters such as the maximum line length; --list-rules shows them all.
+A file can suppress rules from a line onwards with
+<!-- adr-gen-disable rule-id --> and restore them with
+<!-- adr-gen-enable rule-id -->. Without rule IDs, the comment applies to
+every rule.
+
+By default every ADR is checked. Pass ADR files or directories, or
+--since <git-ref> to select the ADRs changed since that ref (including
+uncommitted and untracked files), and only those get per-file checks and
+fixes. Rules that span the log, such as numbering and supersession links,
+still run against every ADR. Paths that are not ADRs are ignored, so a
+list of changed files can be passed as-is:
+
+  adr-gen validate adr/0012-use-grpc.md
+  adr-gen validate --since origin/main
+
+validate exits with status 1 when errors remain, or when there are more
+warnings than --max-warnings allows, so CI fails on broken ADRs.
+
+Known issues can be recorded in a baseline file (default
+.adr-baseline.json) with --update-baseline. Issues in the baseline are
+ignored afterwards, so only new ones fail the build; this lets a legacy
+log adopt --strict one step at a time. Issues are matched by rule, file
+and message, so they survive edits that move them to another line:
+
+  adr-gen validate --strict --update-baseline
+  adr-gen validate --strict --max-warnings 0
 
 Use --strict for additional style checks and --fix to automatically
-correct common issues.`,
+correct common issues. Fixes are written atomically; add --dry-run to
+print them as a unified diff without touching any file.
+
+Use --format to choose the output:
+  text    Human-readable summary (default)
+  json    JSON document with a summary and every issue
+  sarif   SARIF 2.1.0 log for code scanning dashboards
+  junit   JUnit XML for CI test reports
+  github  GitHub Actions annotations
+
+Every format reports the rule ID, severity, file, line, column and whether
+--fix can correct the issue. With a machine-readable format, progress and
+fix messages are written to stderr so stdout only holds the report.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if listRules {
+			printRules()
+			return
+		}
+		if !validReportFormat(reportFormat) {
+			log.Fatalf("Unknown format %q (valid: %s)", reportFormat, strings.Join(validator.ReportFormats, ", "))
+		}
+
+		// Keep stdout clean for machine-readable reports
+		var status io.Writer = os.Stdout
+		if reportFormat != validator.FormatText {
+			status = os.Stderr
+		}
+
+		// Previewing fixes implies computing them
+		if dryRun {
+			fix = true
+		}
+
 		if verbose {
-			fmt.Printf("🔍 Validating ADR files...\n")
+			fmt.Fprintf(status, "🔍 Validating ADR files...\n")
 			if strict {
-				fmt.Printf("   Mode: strict validation\n")
+				fmt.Fprintf(status, "   Mode: strict validation\n")
 			}
 			if fix {
-				fmt.Printf("   Auto-fix: enabled\n")
+				fmt.Fprintf(status, "   Auto-fix: enabled\n")
 			}
+			if dryRun {
+				fmt.Fprintf(status, "   Dry run: fixes will not be written\n")
+			}
+		}
+
+		// Load configuration
+		cfg := loadConfig(cmd, nil)
+
+		if verbose {
+			fmt.Fprintf(status, "   ADR Directory: %s\n", cfg.ADRDirectory)
+		}
+
+		paths := selectedPaths(args)
+		if updateBaseline && paths != nil {
+			log.Fatalf("--update-baseline records issues across every ADR; do not combine it with paths or --since")
+		}
+		if verbose && paths != nil {
+			fmt.Fprintf(status, "   Selected paths: %d\n", len(paths))
 		}
 
-		config := &validator.Config{
+		v := validator.New(&validator.Config{
 			Strict:  strict,
 			Fix:     fix,
+			DryRun:  dryRun,
 			Verbose: verbose,
-		}
-
-		v := validator.New(config)
+			Project: cfg,
+			Paths:   paths,
+			BaseRef: since,
+		})
 		result, err := v.ValidateAll()
 		if err != nil {
 			log.Fatalf("Validation failed: %v", err)
 		}
 
+		if paths != nil && result.FileCount == 0 {
+			fmt.Fprintf(status, "💡 No ADRs selected; only checks across the log were run\n")
+		}
+
+		// Print fixes
+		for _, change := range result.Changes {
+			if dryRun {
+				fmt.Fprint(status, change.Diff)
+			} else if verbose {
+				fmt.Fprintf(status, "🔧 Fixed %s\n", change.File)
+			}
+		}
+		if fix && result.FixCount > 0 {
+			if dryRun {
+				fmt.Fprintf(status, "🔧 Would auto-fix %d issues in %d files\n", result.FixCount, len(result.Changes))
+			} else {
+				fmt.Fprintf(status, "🔧 Auto-fixed %d issues in %d files\n", result.FixCount, len(result.Changes))
+			}
+		}
+
+		if updateBaseline {
+			if err := validator.NewBaseline(result).Save(baselineFile); err != nil {
+				log.Fatalf("Failed to update baseline: %v", err)
+			}
+			fmt.Fprintf(status, "📌 Recorded %d known issues in %s\n", len(result.Issues), baselineFile)
+			return
+		}
+
+		// Drop known issues recorded in the baseline
+		stale := 0
+		if baselineFile != "" {
+			baseline, err := validator.LoadBaseline(baselineFile)
+			switch {
+			case err == nil:
+				// Entries of rules that did not run are not fixed, only skipped
+				for _, entry := range baseline.Apply(result) {
+					if v.RuleEnabled(entry.Rule) {
+						stale++
+					}
+				}
+			case !errors.Is(err, os.ErrNotExist):
+				log.Fatalf("Failed to load baseline: %v", err)
+			}
+		}
+
+		tooManyWarnings := maxWarnings >= 0 && result.WarningCount > maxWarnings
+		failed := result.HasErrors() || tooManyWarnings
+
+		if result.BaselineCount > 0 {
+			fmt.Fprintf(status, "📌 Ignored %d known issues recorded in %s\n", result.BaselineCount, baselineFile)
+		}
+		// Entries for unselected files are not stale, so only report on full runs
+		if stale > 0 && paths == nil {
+			fmt.Fprintf(status, "💡 %d baselined issues no longer occur; run with --update-baseline to tighten the baseline\n", stale)
+		}
+
+		if reportFormat != validator.FormatText {
+			if err := validator.WriteReport(os.Stdout, reportFormat, result, cfg.ADRDirectory); err != nil {
+				log.Fatalf("Failed to write report: %v", err)
+			}
+			if failed {
+				os.Exit(1)
+			}
+			return
+		}
+
 		// Print results
-		if result.HasErrors() {
-			fmt.Printf("❌ Validation failed (%d errors, %d warnings)\n",
-				result.ErrorCount, result.WarningCount)
+		if failed {
+			if result.HasErrors() {
+				fmt.Printf("❌ Validation failed (%d errors, %d warnings)\n",
+					result.ErrorCount, result.WarningCount)
+			} else {
+				fmt.Printf("❌ Validation failed (%d warnings, more than the maximum of %d)\n",
+					result.WarningCount, maxWarnings)
+			}
 
 			for _, issue := range result.Issues {
 				icon := "⚠️"
 				if issue.Level == "error" {
 					icon = "❌"
 				}
-				fmt.Printf("%s %s:%d: %s\n", icon, issue.File, issue.Line, issue.Message)
+				location := fmt.Sprintf("%s:%d", issue.File, issue.Line)
+				if issue.Column > 0 {
+					location += fmt.Sprintf(":%d", issue.Column)
+				}
+				fmt.Printf("%s %s: %s\n", icon, location, issue.Message)
+				if issue.Suggestion != "" {
+					fmt.Printf("   💡 %s\n", issue.Suggestion)
+				}
 			}
+			os.Exit(1)
+		}
 
-			if fix && result.FixCount > 0 {
-				fmt.Printf("🔧 Auto-fixed %d issues\n", result.FixCount)
-			}
-		} else {
-			fmt.Printf("✅ All ADRs are valid!\n")
-			if verbose {
-				fmt.Printf("📊 Validation stats:\n")
-				fmt.Printf("   • %d ADRs checked\n", result.FileCount)
-				fmt.Printf("   • %d diagrams validated\n", result.DiagramCount)
-				if result.WarningCount > 0 {
-					fmt.Printf("   • %d warnings (non-blocking)\n", result.WarningCount)
-				}
+		fmt.Printf("✅ All ADRs are valid!\n")
+		if verbose {
+			fmt.Printf("📊 Validation stats:\n")
+			fmt.Printf("   • %d ADRs checked\n", result.FileCount)
+			fmt.Printf("   • %d diagrams validated\n", result.DiagramCount)
+			if result.WarningCount > 0 {
+				fmt.Printf("   • %d warnings (non-blocking)\n", result.WarningCount)
 			}
 		}
 
 		// Suggestions for improvement
-		if !strict && !result.HasErrors() {
+		if !strict {
 			fmt.Printf("💡 Tip: Run with --strict for additional style checks\n")
 		}
 	},
@@ -96,4 +284,76 @@ func init() {
 
 	validateCmd.Flags().BoolVar(&strict, "strict", false, "enable strict validation with additional style checks")
 	validateCmd.Flags().BoolVar(&fix, "fix", false, "automatically fix common issues")
+	validateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print fixes as a unified diff without writing them (implies --fix)")
+	validateCmd.Flags().BoolVar(&listRules, "list-rules", false, "list the validation rules and their parameters")
+	validateCmd.Flags().StringVar(&since, "since", "", "only check ADRs changed since this git ref, e.g. origin/main")
+	validateCmd.Flags().IntVar(&maxWarnings, "max-warnings", -1, "fail when there are more than this many warnings (-1 for no limit)")
+	validateCmd.Flags().StringVar(&baselineFile, "baseline", validator.DefaultBaselineFile, "file of known issues to ignore (empty to disable)")
+	validateCmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "record the current issues in the baseline file and exit successfully")
+	validateCmd.Flags().StringVar(&reportFormat, "format", validator.FormatText, "output format: "+strings.Join(validator.ReportFormats, ", "))
+}
+
+// selectedPaths combines explicit path arguments with the files changed
+// since --since. It returns nil when neither is given, meaning every ADR.
+func selectedPaths(args []string) []string {
+	if len(args) == 0 && since == "" {
+		return nil
+	}
+
+	paths := append([]string{}, args...)
+	if since != "" {
+		repo, err := gitmeta.Open(".")
+		if err != nil {
+			log.Fatalf("--since needs a git repository: %v", err)
+		}
+		changed, err := repo.ChangedFiles(since)
+		if err != nil {
+			log.Fatalf("Failed to find changed files: %v", err)
+		}
+		paths = append(paths, changed...)
+	}
+	return paths
+}
+
+// validReportFormat reports whether format is a supported --format value
+func validReportFormat(format string) bool {
+	for _, f := range validator.ReportFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// printRules lists the registered validation rules
+func printRules() {
+	// An unset sections list means the sections of the ADR's format
+	formatSections := "(format default)"
+	if cfg, err := config.LoadConfig(cfgFile, nil); err == nil {
+		if format, err := adrformat.Lookup(cfg.DefaultFormat, cfg); err == nil {
+			formatSections = fmt.Sprintf("(format default; %s: %s)", format.Name, strings.Join(format.RequiredSections, ", "))
+		}
+	}
+
+	fmt.Printf("📋 Validation rules:\n")
+	for _, rule := range validator.Rules {
+		id := rule.ID
+		if rule.Strict {
+			id += " (strict)"
+		}
+		fmt.Printf("   %-28s %s\n", id, rule.Description)
+
+		names := make([]string, 0, len(rule.Params))
+		for name := range rule.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value := fmt.Sprint(rule.Params[name])
+			if list, ok := rule.Params[name].([]string); ok && len(list) == 0 && rule.ID == validator.RuleRequiredSections {
+				value = formatSections
+			}
+			fmt.Printf("   %-28s   %s: %s\n", "", name, value)
+		}
+	}
 }
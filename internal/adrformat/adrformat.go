@@ -0,0 +1,285 @@
+package adrformat
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/config"
+)
+
+// Built-in format names
+const (
+	Nygard     = "nygard"
+	MADR       = "madr"
+	YStatement = "y-statement"
+)
+
+// yStatementPattern matches the opening clause of a Y-statement
+var yStatementPattern = regexp.MustCompile(`(?im)^\s*[*_]*in the context of\b`)
+
+// Format describes the structure of one ADR style
+type Format struct {
+	Name             string
+	Description      string
+	RequiredSections []string // H2 headings every ADR of this format must have
+	Template         string   // Skeleton using the {{number}}, {{title}}, ... variables
+}
+
+// builtins holds the formats that ship with adr-gen
+var builtins = map[string]*Format{
+	Nygard: {
+		Name:             Nygard,
+		Description:      "Michael Nygard's Status / Context / Decision / Consequences",
+		RequiredSections: []string{"Status", "Context", "Decision", "Consequences"},
+		Template:         nygardTemplate,
+	},
+	MADR: {
+		Name:             MADR,
+		Description:      "Markdown Architectural Decision Records with considered options",
+		RequiredSections: []string{"Context and Problem Statement", "Considered Options", "Decision Outcome"},
+		Template:         madrTemplate,
+	},
+	YStatement: {
+		Name:             YStatement,
+		Description:      "A single Y-statement sentence capturing context, decision and trade-offs",
+		RequiredSections: []string{"Status", "Decision"},
+		Template:         yStatementTemplate,
+	},
+}
+
+// Names returns the built-in and configured format names, sorted
+func Names(cfg *config.Config) []string {
+	names := make([]string, 0, len(builtins)+len(cfg.Formats))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	for name := range cfg.Formats {
+		if _, ok := builtins[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the named format. Formats declared in the configuration
+// take precedence over built-ins of the same name, and inherit their
+// template and required sections when those are left out.
+func Lookup(name string, cfg *config.Config) (*Format, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		key = cfg.DefaultFormat
+	}
+
+	builtin, isBuiltin := builtins[key]
+	custom, isCustom := cfg.Formats[key]
+	if !isCustom {
+		if !isBuiltin {
+			return nil, fmt.Errorf("unknown ADR format %q (available: %s)", name, strings.Join(Names(cfg), ", "))
+		}
+		return builtin, nil
+	}
+
+	format := &Format{Name: key}
+	if isBuiltin {
+		*format = *builtin
+	}
+	if custom.Description != "" {
+		format.Description = custom.Description
+	}
+	if len(custom.RequiredSections) > 0 {
+		format.RequiredSections = custom.RequiredSections
+	}
+	if custom.Template != "" {
+		data, err := os.ReadFile(custom.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template for format %s: %w", key, err)
+		}
+		format.Template = string(data)
+	}
+	if format.Template == "" {
+		return nil, fmt.Errorf("format %s has no template", key)
+	}
+
+	return format, nil
+}
+
+// Detect infers the format of an ADR body. An explicit front matter format
+// wins; otherwise the headings are compared against the built-in formats,
+// falling back to the configured default.
+func Detect(declared, body string, cfg *config.Config) string {
+	if declared != "" {
+		return strings.ToLower(strings.TrimSpace(declared))
+	}
+
+	sections := Sections(body)
+	switch {
+	case sections["decision outcome"] || sections["considered options"]:
+		return MADR
+	case sections["context"] || sections["consequences"]:
+		return Nygard
+	case yStatementPattern.MatchString(body):
+		return YStatement
+	}
+	return cfg.DefaultFormat
+}
+
+// Sections returns the lower-cased H2 headings of body, ignoring code blocks
+func Sections(body string) map[string]bool {
+	sections := make(map[string]bool)
+	inCode := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if !inCode && strings.HasPrefix(trimmed, "## ") {
+			sections[strings.ToLower(strings.TrimSpace(trimmed[3:]))] = true
+		}
+	}
+	return sections
+}
+
+// nygardTemplate is the classic format from Michael Nygard's original post
+const nygardTemplate = `# {{title}}
+
+## Status
+
+{{status}}
+
+## Category
+
+{{category}}
+
+## Context
+
+*Describe the context and problem statement that led to this decision.*
+
+The issue motivating this decision, and any context that influences or constrains the decision.
+
+## Decision
+
+*Describe the decision that was made.*
+
+We will...
+
+### Rationale
+
+*Explain why this decision was made.*
+
+### Alternatives Considered
+
+*List other options that were considered and why they were not chosen.*
+
+## Consequences
+
+### Positive
+
+- *List positive consequences of this decision*
+
+### Negative
+
+- *List negative consequences of this decision*
+
+### Neutral
+
+- *List neutral consequences that should be noted*
+
+## Implementation
+
+### Next Steps
+
+- [ ] Task 1
+- [ ] Task 2
+- [ ] Task 3
+
+### Timeline
+
+*Describe the implementation timeline and milestones.*
+
+## Related Decisions
+
+*Link to related ADRs or decisions.*
+
+---
+
+*This ADR was created on {{date}} by {{author}}*`
+
+// madrTemplate follows MADR 3, keeping metadata in front matter
+const madrTemplate = `---
+status: {{status}}
+date: {{date}}
+deciders: {{author}}
+category: {{category}}
+format: madr
+---
+# {{title}}
+
+## Context and Problem Statement
+
+*Describe the context and problem statement in two or three sentences, possibly as a question.*
+
+## Decision Drivers
+
+- *Driver 1, e.g. a force or facing concern*
+- *Driver 2*
+
+## Considered Options
+
+- *Option 1*
+- *Option 2*
+- *Option 3*
+
+## Decision Outcome
+
+Chosen option: "*Option 1*", because *justification*.
+
+### Consequences
+
+- Good, because *positive consequence*
+- Bad, because *negative consequence*
+
+## Pros and Cons of the Options
+
+### *Option 1*
+
+- Good, because *argument*
+- Bad, because *argument*
+
+### *Option 2*
+
+- Good, because *argument*
+- Bad, because *argument*
+
+## More Information
+
+*Links to related ADRs, follow-up work or validation of the decision.*`
+
+// yStatementTemplate captures the decision in a single structured sentence
+const yStatementTemplate = `# {{title}}
+
+## Status
+
+{{status}}
+
+## Category
+
+{{category}}
+
+## Decision
+
+In the context of *the use case or component*,
+facing *the non-functional concern*,
+we decided for *the chosen option*
+and neglected *the other options*,
+to achieve *the desired qualities*,
+accepting *the downsides*,
+because *the additional rationale*.
+
+---
+
+*This ADR was created on {{date}} by {{author}}*`
@@ -0,0 +1,236 @@
+package adrfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/euforicio/adr-demo/internal/gitmeta"
+)
+
+// TemplateFile is the ADR template, which is never treated as an ADR
+const TemplateFile = "template.md"
+
+var (
+	nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+	multiHyphen  = regexp.MustCompile(`-+`)
+)
+
+// File is an ADR markdown file found in the ADR directory
+type File struct {
+	Path   string // Path including the ADR directory, e.g. adr/security/0012-foo.md
+	Name   string // Base filename, e.g. 0012-foo.md
+	Folder string // Category folder relative to the ADR directory ("" at the top level)
+}
+
+// RelPath returns the path relative to the ADR directory
+func (f File) RelPath() string {
+	if f.Folder == "" {
+		return f.Name
+	}
+	return filepath.ToSlash(filepath.Join(f.Folder, f.Name))
+}
+
+// Number returns the numeric ADR prefix of the filename, or -1
+func (f File) Number() int {
+	if len(f.Name) < 4 {
+		return -1
+	}
+	num, err := strconv.Atoi(f.Name[:4])
+	if err != nil {
+		return -1
+	}
+	return num
+}
+
+// Discover lists the markdown files in dir and its category folders, one
+// level deep, skipping the template and hidden entries. Only folders named
+// after one of categories (as slugs, e.g. data-management) are read, so that
+// folders such as assets/ or drafts/ are left alone. Files are sorted by
+// name so numbering checks see them in order regardless of folder.
+func Discover(dir string, categories []string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ADR directory: %w", err)
+	}
+
+	files := make([]File, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		if entry.IsDir() {
+			folder := entry.Name()
+			if !isCategoryFolder(folder, categories) {
+				continue
+			}
+			sub, err := os.ReadDir(filepath.Join(dir, folder))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read category folder %s: %w", folder, err)
+			}
+			for _, subEntry := range sub {
+				if isMarkdown(subEntry) {
+					files = append(files, File{
+						Path:   filepath.Join(dir, folder, subEntry.Name()),
+						Name:   subEntry.Name(),
+						Folder: folder,
+					})
+				}
+			}
+			continue
+		}
+
+		if isMarkdown(entry) {
+			files = append(files, File{
+				Path: filepath.Join(dir, entry.Name()),
+				Name: entry.Name(),
+			})
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Name != files[j].Name {
+			return files[i].Name < files[j].Name
+		}
+		return files[i].Folder < files[j].Folder
+	})
+
+	return files, nil
+}
+
+// isCategoryFolder reports whether folder is named after one of categories
+func isCategoryFolder(folder string, categories []string) bool {
+	slug := Slugify(folder)
+	for _, category := range categories {
+		if Slugify(category) == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxNumber returns the highest ADR number in dir and its category folders
+func MaxNumber(dir string, categories []string) (int, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	files, err := Discover(dir, categories)
+	if err != nil {
+		return 0, err
+	}
+
+	max := 0
+	for _, file := range files {
+		if num := file.Number(); num > max {
+			max = num
+		}
+	}
+	return max, nil
+}
+
+// Find returns the ADR file with the given number. It fails when no file
+// or more than one file uses the number.
+func Find(dir string, categories []string, number int) (File, error) {
+	files, err := Discover(dir, categories)
+	if err != nil {
+		return File{}, err
+	}
+
+	var found []File
+	for _, file := range files {
+		if file.Number() == number {
+			found = append(found, file)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return File{}, fmt.Errorf("ADR %04d not found in %s", number, dir)
+	case 1:
+		return found[0], nil
+	default:
+		return File{}, fmt.Errorf("ADR number %04d is used by %d files; run adr-gen validate for a suggested renumber", number, len(found))
+	}
+}
+
+// SortByAge orders files sharing a number by their first commit, oldest
+// first. Uncommitted files, such as one added on the current branch, sort
+// last; without git the path order is kept.
+func SortByAge(files []File) {
+	repo, err := gitmeta.Open(".")
+	if err != nil {
+		return
+	}
+
+	created := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		if history, err := repo.FileHistory(file.Path); err == nil && history != nil {
+			created[file.Path] = history.CreatedAt
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		a, aOK := created[files[i].Path]
+		b, bOK := created[files[j].Path]
+		if aOK != bOK {
+			return aOK
+		}
+		return aOK && a.Before(b)
+	})
+}
+
+// Slugify converts a string to the kebab-case form used in filenames and
+// category folders
+func Slugify(s string) string {
+	s = strings.ToLower(s)
+	s = nonSlugChars.ReplaceAllString(s, "-")
+	s = strings.Trim(s, "-")
+	return multiHyphen.ReplaceAllString(s, "-")
+}
+
+// isMarkdown reports whether entry is an ADR candidate file
+func isMarkdown(entry os.DirEntry) bool {
+	name := entry.Name()
+	return !entry.IsDir() &&
+		!strings.HasPrefix(name, ".") &&
+		strings.HasSuffix(name, ".md") &&
+		name != TemplateFile
+}
+
+// WriteFileAtomic writes data to a temporary file and renames it over path
+// so readers never observe a partially written ADR
+func WriteFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, path)
+}
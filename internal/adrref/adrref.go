@@ -0,0 +1,205 @@
+package adrref
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RelationType identifies the kind of relationship between two ADRs
+type RelationType string
+
+const (
+	// Supersedes means the ADR replaces the target
+	Supersedes RelationType = "supersedes"
+	// SupersededBy means the ADR has been replaced by the target
+	SupersededBy RelationType = "superseded-by"
+
+	// Typed links written under Related Decisions by "adr-gen link"
+	Amends        RelationType = "amends"
+	AmendedBy     RelationType = "amended-by"
+	DependsOn     RelationType = "depends-on"
+	DependedOnBy  RelationType = "depended-on-by"
+	RelatesTo     RelationType = "relates-to"
+	ConflictsWith RelationType = "conflicts-with"
+)
+
+// RelatedHeading is the section that holds typed links to other ADRs
+const RelatedHeading = "Related Decisions"
+
+// labels are the phrases that introduce each relation in an ADR
+var labels = map[RelationType]string{
+	Supersedes:    "Supersedes",
+	SupersededBy:  "Superseded by",
+	Amends:        "Amends",
+	AmendedBy:     "Amended by",
+	DependsOn:     "Depends on",
+	DependedOnBy:  "Depended on by",
+	RelatesTo:     "Relates to",
+	ConflictsWith: "Conflicts with",
+}
+
+var (
+	// Matches "Superseded by [ADR-0010: ...](0010-....md)" or "Superseded by ADR-0010",
+	// also when the link points into another category folder
+	supersededByPattern = regexp.MustCompile(`(?i)superseded\s+by:?\s*\**\s*(?:\[[^\]]*\]\((?:[^)]*/)?(\d{4})-[^)]*\)|ADR-(\d{4}))`)
+	// Matches "Supersedes [ADR-0009: ...](0009-....md)" or "Supersedes ADR-0009"
+	supersedesPattern = regexp.MustCompile(`(?i)\bsupersedes:?\s*\**\s*(?:\[[^\]]*\]\((?:[^)]*/)?(\d{4})-[^)]*\)|ADR-(\d{4}))`)
+	// Matches any ADR mention, used to detect sentences about another ADR
+	adrMentionPattern = regexp.MustCompile(`ADR-\d{4}`)
+	// Matches the numeric part of an ADR reference such as "ADR-0008" or "0008-foo.md"
+	adrRefPattern = regexp.MustCompile(`(?i)^(?:adr-?)?(\d{1,4})`)
+
+	// relatedEntryPattern matches a typed entry under Related Decisions, e.g.
+	// "- Depends on [ADR-0003: ...](0003-....md)" or "- Amends ADR-0005"
+	relatedEntryPattern = regexp.MustCompile(`(?i)^[-*]\s+(amends|amended by|depends on|depended on by|relates to|conflicts with):?\s+(?:\[[^\]]*\]\((?:[^)]*/)?(\d{4})-[^)]*\)|ADR-(\d{4}))`)
+)
+
+// Inverse returns the relation type that mirrors t
+func (t RelationType) Inverse() RelationType {
+	switch t {
+	case Supersedes:
+		return SupersededBy
+	case SupersededBy:
+		return Supersedes
+	case Amends:
+		return AmendedBy
+	case AmendedBy:
+		return Amends
+	case DependsOn:
+		return DependedOnBy
+	case DependedOnBy:
+		return DependsOn
+	}
+	return t
+}
+
+// Label returns the phrase used for t in ADRs and on the site, such as
+// "Depends on"
+func (t RelationType) Label() string {
+	if label, ok := labels[t]; ok {
+		return label
+	}
+	return string(t)
+}
+
+// NormalizeNumber converts references like "8", "ADR-0008" or
+// "0008-use-mongodb.md" into the four digit form used in filenames
+func NormalizeNumber(ref string) string {
+	matches := adrRefPattern.FindStringSubmatch(strings.TrimSpace(ref))
+	if matches == nil {
+		return ""
+	}
+	num, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%04d", num)
+}
+
+// StatusWord returns the status itself from a status line that may carry
+// emphasis or more text, such as "**Superseded** by ADR-0010"
+func StatusWord(status string) string {
+	fields := strings.Fields(status)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.Trim(fields[0], "*_:.,")
+}
+
+// Supersessions finds supersession references written in prose, ignoring
+// code blocks and sentences that describe a different ADR
+func Supersessions(content string) map[RelationType][]string {
+	refs := make(map[RelationType][]string)
+	inCode := false
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		patterns := map[RelationType]*regexp.Regexp{
+			SupersededBy: supersededByPattern,
+			Supersedes:   supersedesPattern,
+		}
+		for relType, pattern := range patterns {
+			for _, loc := range pattern.FindAllStringSubmatchIndex(line, -1) {
+				// "ADR-0009 was superseded by ..." describes another ADR
+				if adrMentionPattern.MatchString(line[:loc[0]]) {
+					continue
+				}
+				ref := ""
+				if loc[2] >= 0 {
+					ref = line[loc[2]:loc[3]]
+				} else if loc[4] >= 0 {
+					ref = line[loc[4]:loc[5]]
+				}
+				if ref != "" {
+					refs[relType] = append(refs[relType], ref)
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+// Related finds the typed entries of the Related Decisions section, at any
+// heading level. Other list items in the section, such as free-text notes,
+// are ignored.
+func Related(content string) map[RelationType][]string {
+	refs := make(map[RelationType][]string)
+	inRelated, inCode := false, false
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			inRelated = IsRelatedHeading(trimmed)
+			continue
+		}
+		if !inRelated {
+			continue
+		}
+
+		m := relatedEntryPattern.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+		ref := m[2]
+		if ref == "" {
+			ref = m[3]
+		}
+		if relType, ok := relationForLabel(m[1]); ok {
+			refs[relType] = append(refs[relType], ref)
+		}
+	}
+
+	return refs
+}
+
+// IsRelatedHeading reports whether a heading line opens Related Decisions
+func IsRelatedHeading(trimmed string) bool {
+	return strings.EqualFold(strings.TrimSpace(strings.TrimLeft(trimmed, "#")), RelatedHeading)
+}
+
+// relationForLabel returns the relation type introduced by label
+func relationForLabel(label string) (RelationType, bool) {
+	for relType, l := range labels {
+		if strings.EqualFold(l, label) {
+			return relType, true
+		}
+	}
+	return "", false
+}
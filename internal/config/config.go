@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/euforicio/adr-demo/internal/adrfs"
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,6 +18,43 @@ type StatusConfig struct {
 	CSSClass string `yaml:"css_class"`
 }
 
+// GlobalConfigName is the per-user configuration file in the home directory
+const GlobalConfigName = ".adr-gen.yaml"
+
+// repoConfigNames are looked up in the working directory when no
+// configuration file is given
+var repoConfigNames = []string{
+	"adr-config.yaml",
+	"adr-config.yml",
+	".adr-config.yaml",
+	".adr-config.yml",
+}
+
+// FormatConfig declares a custom ADR format, or overrides a built-in one
+type FormatConfig struct {
+	Description      string   `yaml:"description"`
+	Template         string   `yaml:"template"`          // Path to the skeleton markdown file
+	RequiredSections []string `yaml:"required_sections"` // H2 headings the validator requires
+}
+
+// RuleConfig enables, disables and tunes a single validation rule. Keys
+// other than enabled and severity are rule parameters, such as max for
+// line-length.
+type RuleConfig struct {
+	Enabled  *bool                  `yaml:"enabled"`  // Unset keeps the rule's default
+	Severity string                 `yaml:"severity"` // "error" or "warning"; unset keeps each issue's level
+	Params   map[string]interface{} `yaml:",inline"`
+}
+
+// GlossaryTerm defines a preferred term and the variants to avoid
+type GlossaryTerm struct {
+	Definition string   `yaml:"definition"` // Shown on the generated glossary page
+	Variants   []string `yaml:"variants"`   // Banned spellings or synonyms, flagged by the validator
+}
+
+// Glossary maps preferred terms to their definitions and banned variants
+type Glossary map[string]GlossaryTerm
+
 // Config holds the complete ADR tool configuration
 type Config struct {
 	ADRDirectory      string                  `yaml:"adr_directory"`
@@ -24,10 +64,17 @@ type Config struct {
 	AllowedCategories []string                `yaml:"allowed_categories"`
 	AllowedStatuses   []string                `yaml:"allowed_statuses"`
 	StatusConfig      map[string]StatusConfig `yaml:"status_config"`
+	StatusTransitions map[string][]string     `yaml:"status_transitions"` // Allowed next statuses, by status
+	CategoryFolders   bool                    `yaml:"category_folders"`   // Create ADRs in per-category sub-folders
+	DefaultFormat     string                  `yaml:"default_format"`
+	Formats           map[string]FormatConfig `yaml:"formats"`
+	Rules             map[string]RuleConfig   `yaml:"rules"`    // Validation rules, by rule ID
+	Glossary          Glossary                `yaml:"glossary"` // Preferred terms and their banned variants
 
 	// Generator settings
-	Minify  bool `yaml:"minify"`
-	Verbose bool `yaml:"verbose"`
+	Minify      bool `yaml:"minify"`
+	Verbose     bool `yaml:"verbose"`
+	GitMetadata bool `yaml:"git_metadata"` // Derive dates and authors from git history
 }
 
 // DefaultConfig returns a configuration with sane defaults
@@ -37,6 +84,7 @@ func DefaultConfig() *Config {
 		OutputDirectory: "docs",
 		BaseURL:         "",
 		DefaultCategory: "General",
+		DefaultFormat:   "nygard",
 		AllowedCategories: []string{
 			"Core Architecture",
 			"Data Management",
@@ -73,104 +121,122 @@ func DefaultConfig() *Config {
 				CSSClass: "bg-purple-500",
 			},
 		},
-		Minify:  false,
-		Verbose: false,
+		Minify:      false,
+		Verbose:     false,
+		GitMetadata: false,
 	}
 }
 
-// LoadConfig loads configuration from a YAML file, falling back to defaults
-func LoadConfig(configPath string) (*Config, error) {
-	config := DefaultConfig()
+// LoadConfig builds the configuration from its layers, each overriding the
+// one before: built-in defaults, the global $HOME/.adr-gen.yaml, the
+// repository file (configPath, or adr-config.yaml when empty), ADR_GEN_*
+// environment variables and finally overrides, which holds the command-line
+// flags that were set, keyed like the YAML file.
+func LoadConfig(configPath string, overrides map[string]string) (*Config, error) {
+	return load(configPath, overrides, false)
+}
 
-	// If no config file specified, look for common names
-	if configPath == "" {
-		candidates := []string{
-			"adr-config.yaml",
-			"adr-config.yml",
-			".adr-config.yaml",
-			".adr-config.yml",
-		}
+// LoadConfigForCreate loads the configuration like LoadConfig, but creates
+// a missing ADR directory instead of failing, so that the first ADR of a
+// fresh project can be written
+func LoadConfigForCreate(configPath string, overrides map[string]string) (*Config, error) {
+	return load(configPath, overrides, true)
+}
 
-		for _, candidate := range candidates {
-			if _, err := os.Stat(candidate); err == nil {
-				configPath = candidate
-				break
-			}
-		}
-	}
+// load builds and validates the layered configuration
+func load(configPath string, overrides map[string]string, createADRDir bool) (*Config, error) {
+	config := DefaultConfig()
 
-	// If still no config file found, return defaults
-	if configPath == "" {
-		return config, nil
+	// Global user configuration, then the repository file
+	files, err := Files(configPath)
+	if err != nil {
+		return nil, err
 	}
-
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return config, nil
+	for _, path := range files {
+		if err := mergeFile(config, path); err != nil {
+			return nil, err
+		}
 	}
 
-	// Read and parse config file
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	// Environment variables
+	if err := applyEnv(config); err != nil {
+		return nil, err
 	}
 
-	// Parse YAML
-	var fileConfig Config
-	if err := yaml.Unmarshal(data, &fileConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+	// Command-line flags
+	for key, value := range overrides {
+		if err := config.Set(key, value); err != nil {
+			return nil, err
+		}
 	}
 
-	// Merge with defaults (file config takes precedence)
-	mergedConfig := mergeConfigs(config, &fileConfig)
-
 	// Validate paths
-	if err := validateConfig(mergedConfig); err != nil {
+	if createADRDir {
+		if err := os.MkdirAll(config.ADRDirectory, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create ADR directory: %w", err)
+		}
+	}
+	if err := validateConfig(config); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	return mergedConfig, nil
+	return config, nil
 }
 
-// mergeConfigs merges file config with defaults, with file config taking precedence
-func mergeConfigs(defaults, fileConfig *Config) *Config {
-	merged := *defaults // Copy defaults
+// Files returns the configuration files that exist, lowest precedence first:
+// the global $HOME/.adr-gen.yaml and the repository file. configPath selects
+// the repository file; when empty ADR_GEN_CONFIG or adr-config.yaml is used.
+// A file named by configPath or ADR_GEN_CONFIG must exist; the default
+// locations are optional.
+func Files(configPath string) ([]string, error) {
+	var files []string
 
-	// Override with file config values if they're not zero values
-	if fileConfig.ADRDirectory != "" {
-		merged.ADRDirectory = fileConfig.ADRDirectory
-	}
-	if fileConfig.OutputDirectory != "" {
-		merged.OutputDirectory = fileConfig.OutputDirectory
+	if home, err := os.UserHomeDir(); err == nil {
+		global := filepath.Join(home, GlobalConfigName)
+		if _, err := os.Stat(global); err == nil {
+			files = append(files, global)
+		}
 	}
-	if fileConfig.BaseURL != "" {
-		merged.BaseURL = fileConfig.BaseURL
+
+	if configPath == "" {
+		configPath = os.Getenv(EnvPrefix + "CONFIG")
 	}
-	if fileConfig.DefaultCategory != "" {
-		merged.DefaultCategory = fileConfig.DefaultCategory
+	if configPath != "" {
+		if _, err := os.Stat(configPath); err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("config file %s not found", configPath)
+			}
+			return nil, err
+		}
+		return append(files, configPath), nil
 	}
-	if len(fileConfig.AllowedCategories) > 0 {
-		merged.AllowedCategories = fileConfig.AllowedCategories
+
+	for _, candidate := range repoConfigNames {
+		if _, err := os.Stat(candidate); err == nil {
+			return append(files, candidate), nil
+		}
 	}
-	if len(fileConfig.AllowedStatuses) > 0 {
-		merged.AllowedStatuses = fileConfig.AllowedStatuses
+	return files, nil
+}
+
+// mergeFile checks the YAML file at path against the schema and decodes it
+// over config, so keys missing from the file keep the value of the lower
+// layers
+func mergeFile(config *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
 
-	// Merge status configs
-	if len(fileConfig.StatusConfig) > 0 {
-		if merged.StatusConfig == nil {
-			merged.StatusConfig = make(map[string]StatusConfig)
-		}
-		for status, config := range fileConfig.StatusConfig {
-			merged.StatusConfig[status] = config
-		}
+	if errs := CheckSchema(path, data); len(errs) > 0 {
+		return errs
 	}
 
-	// Boolean flags
-	merged.Minify = fileConfig.Minify
-	merged.Verbose = fileConfig.Verbose
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
 
-	return &merged
+	return nil
 }
 
 // validateConfig validates the configuration
@@ -188,6 +254,14 @@ func validateConfig(config *Config) error {
 		}
 	}
 
+	if err := validateTransitions(config); err != nil {
+		return err
+	}
+
+	if err := validateGlossary(config.Glossary); err != nil {
+		return err
+	}
+
 	// Validate that all allowed statuses have status configs
 	for _, status := range config.AllowedStatuses {
 		if _, exists := config.StatusConfig[status]; !exists {
@@ -203,6 +277,51 @@ func validateConfig(config *Config) error {
 	return nil
 }
 
+// validateTransitions checks that the transition graph only uses allowed
+// statuses
+func validateTransitions(config *Config) error {
+	from := make([]string, 0, len(config.StatusTransitions))
+	for status := range config.StatusTransitions {
+		from = append(from, status)
+	}
+	sort.Strings(from)
+
+	for _, status := range from {
+		for _, s := range append([]string{status}, config.StatusTransitions[status]...) {
+			if !config.IsValidStatus(s) {
+				return fmt.Errorf("status_transitions: %q is not one of the allowed statuses (%s)",
+					s, strings.Join(config.AllowedStatuses, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// validateGlossary rejects variants that are empty, equal to their own
+// term or claimed by two terms, since the validator could not tell which
+// replacement to suggest
+func validateGlossary(glossary Glossary) error {
+	owners := make(map[string]string)
+	for _, term := range glossary.Terms() {
+		if strings.TrimSpace(term) == "" {
+			return fmt.Errorf("glossary term must not be empty")
+		}
+		for _, variant := range glossary[term].Variants {
+			key := strings.ToLower(strings.TrimSpace(variant))
+			switch {
+			case key == "":
+				return fmt.Errorf("glossary term %q has an empty variant", term)
+			case key == strings.ToLower(term):
+				return fmt.Errorf("glossary term %q lists itself as a variant", term)
+			case owners[key] != "":
+				return fmt.Errorf("glossary variant %q is listed under both %q and %q", variant, owners[key], term)
+			}
+			owners[key] = term
+		}
+	}
+	return nil
+}
+
 // GetStatusIcon returns the icon for a given status
 func (c *Config) GetStatusIcon(status string) string {
 	statusConfig, exists := c.StatusConfig[status]
@@ -230,6 +349,34 @@ func (c *Config) GetStatusColor(status string) string {
 	return statusConfig.Color
 }
 
+// NextStatuses returns the statuses an ADR may move to from status. ok is
+// false when status_transitions does not restrict moves from status: there
+// is no graph, or the status is not listed in it. Statuses compare without
+// case.
+func (c *Config) NextStatuses(status string) (next []string, ok bool) {
+	for from, to := range c.StatusTransitions {
+		if strings.EqualFold(from, status) {
+			return to, true
+		}
+	}
+	return nil, false
+}
+
+// CanTransition reports whether status_transitions allows moving an ADR
+// from one status to another
+func (c *Config) CanTransition(from, to string) bool {
+	next, ok := c.NextStatuses(from)
+	if !ok {
+		return true
+	}
+	for _, status := range next {
+		if strings.EqualFold(status, to) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsValidCategory checks if a category is in the allowed list
 func (c *Config) IsValidCategory(category string) bool {
 	for _, allowed := range c.AllowedCategories {
@@ -249,3 +396,36 @@ func (c *Config) IsValidStatus(status string) bool {
 	}
 	return false
 }
+
+// CategoryForFolder returns the allowed category stored in a sub-folder of
+// the ADR directory, e.g. "core-architecture" maps to "Core Architecture"
+func (c *Config) CategoryForFolder(folder string) (string, bool) {
+	slug := adrfs.Slugify(folder)
+	for _, allowed := range c.AllowedCategories {
+		if adrfs.Slugify(allowed) == slug {
+			return allowed, true
+		}
+	}
+	return "", false
+}
+
+// Terms returns the glossary terms in alphabetical order, ignoring case
+func (g Glossary) Terms() []string {
+	terms := make([]string, 0, len(g))
+	for term := range g {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		a, b := strings.ToLower(terms[i]), strings.ToLower(terms[j])
+		if a != b {
+			return a < b
+		}
+		return terms[i] < terms[j]
+	})
+	return terms
+}
+
+// FolderForCategory returns the sub-folder name used for a category
+func (c *Config) FolderForCategory(category string) string {
+	return adrfs.Slugify(category)
+}
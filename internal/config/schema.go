@@ -0,0 +1,294 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tailwindColors are the color names the status badges can use
+var tailwindColors = map[string]bool{
+	"slate": true, "gray": true, "zinc": true, "neutral": true, "stone": true,
+	"red": true, "orange": true, "amber": true, "yellow": true, "lime": true,
+	"green": true, "emerald": true, "teal": true, "cyan": true, "sky": true,
+	"blue": true, "indigo": true, "violet": true, "purple": true, "fuchsia": true,
+	"pink": true, "rose": true,
+}
+
+var (
+	hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	cssClassPattern = regexp.MustCompile(`^-?[A-Za-z_][A-Za-z0-9_:/.%\[\]-]*$`)
+	// yamlLinePattern finds the line yaml.v3 names in a syntax error
+	yamlLinePattern = regexp.MustCompile(`\bline (\d+):`)
+)
+
+// legacyBools are the YAML 1.1 spellings that yaml.v3 still decodes into bools
+var legacyBools = map[string]bool{"yes": true, "no": true, "on": true, "off": true}
+
+// valueCheckers validate scalar values by key, wherever the key appears
+var valueCheckers = map[string]func(value string) error{
+	"color":     checkColor,
+	"css_class": checkCSSClass,
+	"severity":  checkSeverity,
+}
+
+// SchemaError is a problem found while checking a configuration file
+type SchemaError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error implements the error interface
+func (e SchemaError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// SchemaErrors collects every schema problem found in a file
+type SchemaErrors []SchemaError
+
+// Error implements the error interface
+func (e SchemaErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// CheckSchema validates the YAML in data against the Config schema. It
+// reports unknown keys, values of the wrong type and malformed colors and
+// CSS classes, with the line and column of each.
+func CheckSchema(file string, data []byte) SchemaErrors {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return SchemaErrors{{File: file, Line: syntaxErrorLine(err), Column: 1, Message: err.Error()}}
+	}
+	if len(doc.Content) == 0 {
+		return nil // Empty file
+	}
+
+	var errs SchemaErrors
+	checkNode(file, doc.Content[0], reflect.TypeOf(Config{}), "", "", &errs)
+	return errs
+}
+
+// syntaxErrorLine returns the line a YAML syntax error names, or 1
+func syntaxErrorLine(err error) int {
+	if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		if line, convErr := strconv.Atoi(m[1]); convErr == nil {
+			return line
+		}
+	}
+	return 1
+}
+
+// checkNode checks node against the Go type t. path names the node for
+// messages and key is the YAML key it was found under.
+func checkNode(file string, node *yaml.Node, t reflect.Type, path, key string, errs *SchemaErrors) {
+	report := func(n *yaml.Node, format string, args ...interface{}) {
+		*errs = append(*errs, SchemaError{
+			File:    file,
+			Line:    n.Line,
+			Column:  n.Column,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Tag == "!!null" {
+		return // Null clears the value, which is always allowed
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			report(node, "%s must be a mapping", describe(path))
+			return
+		}
+		fields := yamlFields(t)
+		inline := inlineMap(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			field, ok := fields[keyNode.Value]
+			if !ok && inline != nil {
+				checkNode(file, valueNode, inline.Elem(), join(path, keyNode.Value), keyNode.Value, errs)
+				continue
+			}
+			if !ok {
+				message := fmt.Sprintf("unknown key %q%s", keyNode.Value, in(path))
+				if suggestion := closestKey(keyNode.Value, fields); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				report(keyNode, "%s", message)
+				continue
+			}
+			checkNode(file, valueNode, field.Type, join(path, keyNode.Value), keyNode.Value, errs)
+		}
+
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			report(node, "%s must be a mapping", describe(path))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			checkNode(file, valueNode, t.Elem(), join(path, keyNode.Value), "", errs)
+		}
+
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			report(node, "%s must be a list", describe(path))
+			return
+		}
+		for _, item := range node.Content {
+			checkNode(file, item, t.Elem(), path+"[]", key, errs)
+		}
+
+	case reflect.Bool:
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!bool" && !legacyBools[strings.ToLower(node.Value)]) {
+			report(node, "%s must be true or false, got %s", describe(path), got(node))
+		}
+
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			report(node, "%s must be a string, got %s", describe(path), got(node))
+			return
+		}
+		if check, ok := valueCheckers[key]; ok {
+			if err := check(node.Value); err != nil {
+				report(node, "%s: %v", describe(path), err)
+			}
+		}
+	}
+}
+
+// yamlFields maps the YAML keys of a struct type to its fields
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+// inlineMap returns the type of a struct's inline map field, which
+// collects keys that match no other field, or nil if there is none
+func inlineMap(t reflect.Type) reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Map && strings.Contains(field.Tag.Get("yaml"), ",inline") {
+			return field.Type
+		}
+	}
+	return nil
+}
+
+// closestKey suggests a known key within two edits of key, if any
+func closestKey(key string, fields map[string]reflect.StructField) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// checkColor accepts Tailwind color names and hex colors
+func checkColor(value string) error {
+	if tailwindColors[value] || hexColorPattern.MatchString(value) {
+		return nil
+	}
+	return fmt.Errorf("invalid color %q (use a Tailwind color name such as \"green\" or a hex value)", value)
+}
+
+// checkCSSClass accepts space-separated class names. Classes are written
+// into HTML attributes, so quotes and angle brackets are rejected.
+func checkCSSClass(value string) error {
+	classes := strings.Fields(value)
+	if len(classes) == 0 {
+		return fmt.Errorf("CSS class must not be empty")
+	}
+	for _, class := range classes {
+		if !cssClassPattern.MatchString(class) {
+			return fmt.Errorf("invalid CSS class %q", class)
+		}
+	}
+	return nil
+}
+
+// got describes the value of node for type errors
+func got(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.MappingNode:
+		return "a mapping"
+	}
+	return fmt.Sprintf("%q", node.Value)
+}
+
+// join appends key to a dotted path
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// describe names a path for messages
+func describe(path string) string {
+	if path == "" {
+		return "configuration"
+	}
+	return path
+}
+
+// in returns the " in <path>" suffix for nested keys
+func in(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " in " + path
+}
+
+// checkSeverity accepts the levels a validation rule can report at
+func checkSeverity(value string) error {
+	if value != "error" && value != "warning" {
+		return fmt.Errorf("invalid severity %q (use error or warning)", value)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		line    int
+		column  int
+		message string
+	}{
+		{
+			name:    "unknown key",
+			yaml:    "adr_directory: adr\nalowed_statuses: [Proposed]\n",
+			line:    2,
+			column:  1,
+			message: `unknown key "alowed_statuses" (did you mean "allowed_statuses"?)`,
+		},
+		{
+			name:    "wrong type",
+			yaml:    "category_folders: [a]\n",
+			line:    1,
+			column:  19,
+			message: "category_folders must be true or false, got a list",
+		},
+		{
+			name:    "nested color",
+			yaml:    "status_config:\n  Accepted:\n    color: grean\n",
+			line:    3,
+			column:  12,
+			message: `status_config.Accepted.color: invalid color "grean"`,
+		},
+		{
+			name:    "rule severity",
+			yaml:    "rules:\n  trailing-whitespace:\n    severity: fatal\n",
+			line:    3,
+			column:  15,
+			message: `rules.trailing-whitespace.severity: invalid severity "fatal"`,
+		},
+		{
+			name:    "list item",
+			yaml:    "allowed_statuses:\n  - Proposed\n  - {a: b}\n",
+			line:    3,
+			column:  5,
+			message: "allowed_statuses[] must be a string, got a mapping",
+		},
+		{
+			name:    "syntax error",
+			yaml:    "adr_directory: adr\nsite:\n\ttitle: ADRs\n",
+			line:    3,
+			column:  1,
+			message: "yaml: line 3:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := CheckSchema("adr-config.yaml", []byte(tt.yaml))
+			if len(errs) != 1 {
+				t.Fatalf("CheckSchema() = %v, want one error", errs)
+			}
+			err := errs[0]
+			if err.Line != tt.line || err.Column != tt.column {
+				t.Errorf("position = %d:%d, want %d:%d", err.Line, err.Column, tt.line, tt.column)
+			}
+			if !strings.Contains(err.Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", err.Message, tt.message)
+			}
+			if !strings.HasPrefix(err.Error(), "adr-config.yaml:") {
+				t.Errorf("Error() = %q, want it to start with the file name", err.Error())
+			}
+		})
+	}
+}
+
+func TestCheckSchemaAcceptsValidConfig(t *testing.T) {
+	data := "adr_directory: adr\ngit_metadata: false\ncategory_folders: yes\nstatus_config:\n  Accepted:\n    color: \"#22c55e\"\n"
+	if errs := CheckSchema("adr-config.yaml", []byte(data)); len(errs) != 0 {
+		t.Errorf("CheckSchema() = %v, want no errors", errs)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix prefixes the environment variables that override settings,
+// e.g. ADR_GEN_OUTPUT_DIRECTORY overrides output_directory
+const EnvPrefix = "ADR_GEN_"
+
+// setting parses a string value into one configuration field
+type setting func(c *Config, value string) error
+
+// settings lists the keys that can be set from the environment and the
+// command line, named as in the YAML file
+var settings = map[string]setting{
+	"adr_directory":      stringSetting(func(c *Config) *string { return &c.ADRDirectory }),
+	"output_directory":   stringSetting(func(c *Config) *string { return &c.OutputDirectory }),
+	"base_url":           stringSetting(func(c *Config) *string { return &c.BaseURL }),
+	"default_category":   stringSetting(func(c *Config) *string { return &c.DefaultCategory }),
+	"default_format":     stringSetting(func(c *Config) *string { return &c.DefaultFormat }),
+	"allowed_categories": listSetting(func(c *Config) *[]string { return &c.AllowedCategories }),
+	"allowed_statuses":   listSetting(func(c *Config) *[]string { return &c.AllowedStatuses }),
+	"category_folders":   boolSetting(func(c *Config) *bool { return &c.CategoryFolders }),
+	"minify":             boolSetting(func(c *Config) *bool { return &c.Minify }),
+	"verbose":            boolSetting(func(c *Config) *bool { return &c.Verbose }),
+	"git_metadata":       boolSetting(func(c *Config) *bool { return &c.GitMetadata }),
+}
+
+// Set assigns the setting named by its YAML key from a string value
+func (c *Config) Set(key, value string) error {
+	set, ok := settings[key]
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if err := set(c, value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return nil
+}
+
+// SettingKeys returns the keys accepted by Set, sorted
+func SettingKeys() []string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvVar returns the environment variable that overrides key
+func EnvVar(key string) string {
+	return EnvPrefix + strings.ToUpper(key)
+}
+
+// applyEnv applies every ADR_GEN_* variable that names a setting
+func applyEnv(c *Config) error {
+	for _, key := range SettingKeys() {
+		value, ok := os.LookupEnv(EnvVar(key))
+		if !ok {
+			continue
+		}
+		if err := c.Set(key, value); err != nil {
+			return fmt.Errorf("%s: %w", EnvVar(key), err)
+		}
+	}
+	return nil
+}
+
+// stringSetting sets a string field verbatim
+func stringSetting(field func(c *Config) *string) setting {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+// boolSetting parses true/false, 1/0 and the other strconv spellings
+func boolSetting(field func(c *Config) *bool) setting {
+	return func(c *Config, value string) error {
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+// listSetting splits a comma-separated value into a list
+func listSetting(field func(c *Config) *[]string) setting {
+	return func(c *Config, value string) error {
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field(c) = list
+		return nil
+	}
+}
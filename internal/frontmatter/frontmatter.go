@@ -0,0 +1,122 @@
+package frontmatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Delimiter opens and closes a YAML front matter block
+const Delimiter = "---"
+
+// dateLayouts lists the accepted formats for the date field
+var dateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"January 2, 2006",
+}
+
+// FrontMatter holds the metadata declared in an ADR's YAML front matter block
+type FrontMatter struct {
+	Status       string     `yaml:"status"`
+	Date         string     `yaml:"date"`
+	Deciders     StringList `yaml:"deciders"`
+	Tags         StringList `yaml:"tags"`
+	Category     string     `yaml:"category"`
+	Supersedes   StringList `yaml:"supersedes"`
+	SupersededBy StringList `yaml:"superseded_by"`
+	DependsOn    StringList `yaml:"depends_on"`
+	Format       string     `yaml:"format"` // ADR format, e.g. nygard or madr
+}
+
+// StringList accepts either a single YAML scalar or a sequence of scalars
+type StringList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if strings.TrimSpace(value.Value) == "" {
+			*l = nil
+			return nil
+		}
+		*l = StringList{strings.TrimSpace(value.Value)}
+		return nil
+	case yaml.SequenceNode:
+		items := make(StringList, 0, len(value.Content))
+		for _, item := range value.Content {
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: expected a scalar list item", item.Line)
+			}
+			if trimmed := strings.TrimSpace(item.Value); trimmed != "" {
+				items = append(items, trimmed)
+			}
+		}
+		*l = items
+		return nil
+	default:
+		return fmt.Errorf("line %d: expected a string or a list of strings", value.Line)
+	}
+}
+
+// Split separates a leading front matter block from the markdown body.
+// It returns the raw YAML, the remaining body and the number of lines the
+// block occupied (including both delimiters). ok is false when the content
+// does not start with a front matter block.
+func Split(content string) (block, body string, lineCount int, ok bool) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(normalized, "\n")
+
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != Delimiter {
+		return "", content, 0, false
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == Delimiter {
+			block = strings.Join(lines[1:i], "\n")
+			body = strings.Join(lines[i+1:], "\n")
+			return block, body, i + 1, true
+		}
+	}
+
+	// Unterminated block: treat the whole file as body
+	return "", content, 0, false
+}
+
+// Parse extracts and decodes the front matter of an ADR. It returns a nil
+// FrontMatter and the unchanged content when no block is present.
+func Parse(content string) (*FrontMatter, string, error) {
+	block, body, _, ok := Split(content)
+	if !ok {
+		return nil, content, nil
+	}
+
+	var fm FrontMatter
+	if err := yaml.Unmarshal([]byte(block), &fm); err != nil {
+		return nil, body, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	fm.Status = strings.TrimSpace(fm.Status)
+	fm.Category = strings.TrimSpace(fm.Category)
+	fm.Date = strings.TrimSpace(fm.Date)
+
+	return &fm, body, nil
+}
+
+// ParsedDate returns the date field as a time value
+func (fm *FrontMatter) ParsedDate() (time.Time, bool) {
+	if fm == nil || fm.Date == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, fm.Date); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -1203,12 +1203,19 @@ window.addEventListener('resize', function() {
 // generateSearchIndex creates a search index JSON file
 func (g *Generator) generateSearchIndex() error {
 	type SearchItem struct {
-		Number      string `json:"number"`
-		Title       string `json:"title"`
-		Status      string `json:"status"`
-		Content     string `json:"content"`
-		DiagramType string `json:"diagramType"`
-		URL         string `json:"url"`
+		Number       string   `json:"number"`
+		Title        string   `json:"title"`
+		Status       string   `json:"status"`
+		Content      string   `json:"content"`
+		DiagramType  string   `json:"diagramType"`
+		URL          string   `json:"url"`
+		Category     string   `json:"category,omitempty"`
+		Date         string   `json:"date,omitempty"`
+		Author       string   `json:"author,omitempty"`
+		Deciders     []string `json:"deciders,omitempty"`
+		Tags         []string `json:"tags,omitempty"`
+		Supersedes   []string `json:"supersedes,omitempty"`
+		SupersededBy []string `json:"supersededBy,omitempty"`
 	}
 
 	var searchItems []SearchItem
@@ -1225,12 +1232,19 @@ func (g *Generator) generateSearchIndex() error {
 		}
 
 		item := SearchItem{
-			Number:      adr.Number,
-			Title:       adr.Title,
-			Status:      adr.Status,
-			Content:     cleanContent,
-			DiagramType: adr.DiagramType,
-			URL:         fmt.Sprintf("adr-%s.html", adr.Number),
+			Number:       adr.Number,
+			Title:        adr.Title,
+			Status:       adr.Status,
+			Content:      cleanContent,
+			DiagramType:  adr.DiagramType,
+			URL:          fmt.Sprintf("adr-%s.html", adr.Number),
+			Category:     adr.Category,
+			Date:         adr.Date,
+			Author:       adr.Author,
+			Deciders:     adr.Deciders,
+			Tags:         adr.Tags,
+			Supersedes:   adr.Supersedes,
+			SupersededBy: adr.SupersededBy,
 		}
 
 		searchItems = append(searchItems, item)
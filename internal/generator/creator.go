@@ -8,6 +8,11 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/euforicio/adr-demo/internal/adrformat"
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
 )
 
 // ADRConfig holds the ADR creation configuration
@@ -15,8 +20,25 @@ type ADRConfig struct {
 	Title    string
 	Status   string
 	Category string
+	Author   string
+	Format   string // ADR format; empty uses the repository template or default_format
 	Force    bool
 	Verbose  bool
+
+	// Project is the loaded adr-config.yaml (defaults are used when nil)
+	Project *config.Config
+}
+
+// TemplateVars holds the values substituted into an ADR template.
+// Templates reference them as {{number}}, {{title}}, {{status}}, {{date}},
+// {{category}} and {{author}}.
+type TemplateVars struct {
+	Number   string
+	Title    string
+	Status   string
+	Date     string
+	Category string
+	Author   string
 }
 
 // ADRCreator handles creating new ADRs
@@ -25,29 +47,68 @@ type ADRCreator struct {
 }
 
 // NewADRCreator creates a new ADR creator
-func NewADRCreator(config *ADRConfig) *ADRCreator {
+func NewADRCreator(cfg *ADRConfig) *ADRCreator {
+	if cfg.Project == nil {
+		cfg.Project = config.DefaultConfig()
+	}
 	return &ADRCreator{
-		config: config,
+		config: cfg,
 	}
 }
 
+// plannedADR is a rendered ADR that has not been written yet
+type plannedADR struct {
+	Number  int
+	Dir     string // Directory the ADR goes in
+	Path    string // File path of the ADR
+	Content string
+}
+
 // Create creates a new ADR file
 func (c *ADRCreator) Create() (string, error) {
+	planned, err := c.plan()
+	if err != nil {
+		return "", err
+	}
+
+	// Create directory if it doesn't exist
+	if err := os.MkdirAll(planned.Dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create ADR directory: %w", err)
+	}
+
+	// Write file
+	if err := os.WriteFile(planned.Path, []byte(planned.Content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write ADR file: %w", err)
+	}
+
+	return planned.relPath(), nil
+}
+
+// plan numbers and renders the new ADR without writing it
+func (c *ADRCreator) plan() (*plannedADR, error) {
+	project := c.config.Project
+
 	// Get next ADR number
 	nextNumber, err := c.getNextADRNumber()
 	if err != nil {
-		return "", fmt.Errorf("failed to determine next ADR number: %w", err)
+		return nil, fmt.Errorf("failed to determine next ADR number: %w", err)
 	}
 
 	// Create filename and path
 	filename := c.createFilename(nextNumber, c.config.Title)
+	status, err := c.resolveStatus()
+	if err != nil {
+		return nil, err
+	}
+	category, err := c.resolveCategory()
+	if err != nil {
+		return nil, err
+	}
 
-	// Use flat structure following ADR spec
-	adrDir := "adr"
-
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(adrDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create ADR directory: %w", err)
+	// Use flat structure following ADR spec, unless category folders are enabled
+	adrDir := project.ADRDirectory
+	if project.CategoryFolders && c.config.Category != "" {
+		adrDir = filepath.Join(adrDir, project.FolderForCategory(category))
 	}
 
 	filePath := filepath.Join(adrDir, filename)
@@ -55,66 +116,68 @@ func (c *ADRCreator) Create() (string, error) {
 	// Check if file already exists
 	if !c.config.Force {
 		if _, err := os.Stat(filePath); err == nil {
-			return "", fmt.Errorf("ADR file already exists: %s (use --force to overwrite)", filename)
+			return nil, fmt.Errorf("ADR file already exists: %s (use --force to overwrite)", filename)
 		}
 	}
 
 	// Generate ADR content
-	content := c.generateADRContent(nextNumber, c.config.Title, c.config.Status)
-
-	// Write file
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return "", fmt.Errorf("failed to write ADR file: %w", err)
+	content, err := c.generateADRContent(TemplateVars{
+		Number:   fmt.Sprintf("%04d", nextNumber),
+		Title:    c.config.Title,
+		Status:   status,
+		Date:     time.Now().Format("2006-01-02"),
+		Category: category,
+		Author:   c.config.Author,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Return the relative path from the project root
-	return filepath.Join(strings.TrimPrefix(adrDir, "./"), filename), nil
+	return &plannedADR{Number: nextNumber, Dir: adrDir, Path: filePath, Content: content}, nil
 }
 
-// getNextADRNumber determines the next available ADR number from flat structure
-func (c *ADRCreator) getNextADRNumber() (int, error) {
-	adrDir := "adr"
-
-	// If directory doesn't exist, start with 0001
-	if _, err := os.Stat(adrDir); os.IsNotExist(err) {
-		return 1, nil
-	}
-
-	maxNumber := 0
+// relPath returns the path of the ADR relative to the project root
+func (p *plannedADR) relPath() string {
+	return filepath.Join(strings.TrimPrefix(p.Dir, "./"), filepath.Base(p.Path))
+}
 
-	// Read all files in the ADR directory (flat structure)
-	files, err := os.ReadDir(adrDir)
+// getNextADRNumber determines the next available ADR number, unique
+// across the top-level directory and all category folders
+func (c *ADRCreator) getNextADRNumber() (int, error) {
+	maxNumber, err := adrfs.MaxNumber(c.config.Project.ADRDirectory, c.config.Project.AllowedCategories)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read ADR directory: %w", err)
+		return 0, err
 	}
 
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-
-		// Only process .md files
-		if !strings.HasSuffix(file.Name(), ".md") {
-			continue
-		}
-
-		// Skip template
-		if file.Name() == "template.md" {
-			continue
-		}
+	return maxNumber + 1, nil
+}
 
-		// Extract number from filename
-		if len(file.Name()) >= 4 {
-			numStr := file.Name()[:4]
-			if num, err := strconv.Atoi(numStr); err == nil {
-				if num > maxNumber {
-					maxNumber = num
-				}
-			}
-		}
+// resolveCategory maps the requested category onto the configured spelling,
+// falling back to the default category. Categories that are not allowed are
+// rejected.
+func (c *ADRCreator) resolveCategory() (string, error) {
+	project := c.config.Project
+	if c.config.Category == "" {
+		return project.DefaultCategory, nil
+	}
+	if category, ok := project.CategoryForFolder(c.config.Category); ok {
+		return category, nil
 	}
+	if len(project.AllowedCategories) == 0 {
+		return c.config.Category, nil
+	}
+	return "", fmt.Errorf("unknown category %q (allowed: %s)", c.config.Category, strings.Join(project.AllowedCategories, ", "))
+}
 
-	return maxNumber + 1, nil
+// resolveStatus maps the requested status onto the configured spelling, so
+// "accepted" is written as "Accepted". Statuses that are not allowed are
+// rejected.
+func (c *ADRCreator) resolveStatus() (string, error) {
+	status, ok := canonicalStatus(c.config.Project, c.config.Status)
+	if !ok {
+		return "", fmt.Errorf("unknown status %q for the new ADR (allowed: %s)", c.config.Status, strings.Join(c.config.Project.AllowedStatuses, ", "))
+	}
+	return status, nil
 }
 
 // createFilename creates a filename from title
@@ -126,89 +189,67 @@ func (c *ADRCreator) createFilename(number int, title string) string {
 
 // toKebabCase converts a string to kebab-case
 func (c *ADRCreator) toKebabCase(s string) string {
-	// Convert to lowercase
-	s = strings.ToLower(s)
-
-	// Replace spaces and special characters with hyphens
-	re := regexp.MustCompile(`[^a-z0-9]+`)
-	s = re.ReplaceAllString(s, "-")
-
-	// Remove leading/trailing hyphens
-	s = strings.Trim(s, "-")
-
-	// Collapse multiple hyphens
-	re = regexp.MustCompile(`-+`)
-	s = re.ReplaceAllString(s, "-")
-
-	return s
+	return adrfs.Slugify(s)
 }
 
-// generateADRContent generates the content for a new ADR
-func (c *ADRCreator) generateADRContent(number int, title, status string) string {
-	now := time.Now()
-
-	template := `# %s
-
-## Status
-
-%s
-
-## Context
-
-*Describe the context and problem statement that led to this decision.*
-
-The issue motivating this decision, and any context that influences or constrains the decision.
-
-## Decision
-
-*Describe the decision that was made.*
-
-We will...
-
-### Rationale
-
-*Explain why this decision was made.*
-
-### Alternatives Considered
-
-*List other options that were considered and why they were not chosen.*
-
-## Consequences
-
-### Positive
-
-- *List positive consequences of this decision*
-
-### Negative
-
-- *List negative consequences of this decision*
-
-### Neutral
-
-- *List neutral consequences that should be noted*
-
-## Implementation
-
-### Next Steps
-
-- [ ] Task 1
-- [ ] Task 2
-- [ ] Task 3
-
-### Timeline
-
-*Describe the implementation timeline and milestones.*
-
-## Related Decisions
+// generateADRContent renders the requested format. Without an explicit
+// format the repository's template.md is used, falling back to the
+// configured default format.
+func (c *ADRCreator) generateADRContent(vars TemplateVars) (string, error) {
+	if c.config.Format == "" {
+		templatePath := filepath.Join(c.config.Project.ADRDirectory, adrfs.TemplateFile)
+		data, err := os.ReadFile(templatePath)
+		switch {
+		case err == nil:
+			if c.config.Verbose {
+				fmt.Printf("   Template: %s\n", templatePath)
+			}
+			return RenderTemplate(string(data), vars), nil
+		case !os.IsNotExist(err):
+			return "", fmt.Errorf("failed to read ADR template: %w", err)
+		}
+	}
 
-*Link to related ADRs or decisions.*
+	format, err := adrformat.Lookup(c.config.Format, c.config.Project)
+	if err != nil {
+		return "", err
+	}
+	if c.config.Verbose {
+		fmt.Printf("   Format: %s\n", format.Name)
+	}
 
----
+	return RenderTemplate(format.Template, vars), nil
+}
 
-*This ADR was created on %s*`
+// frontMatterVarPattern matches a front matter value that is a single
+// free-text template variable, such as "deciders: {{author}}"
+var frontMatterVarPattern = regexp.MustCompile(`^(\s*(?:[\w-]+:|-)\s*)\{\{(title|author|category)\}\}\s*$`)
+
+// RenderTemplate substitutes the template variables in tmpl. Free-text
+// values that make up a whole front matter value are written as quoted
+// YAML strings, so that a ":" or "#" in them cannot break the block.
+func RenderTemplate(tmpl string, vars TemplateVars) string {
+	values := map[string]string{
+		"title":    vars.Title,
+		"author":   vars.Author,
+		"category": vars.Category,
+	}
+	if _, _, blockLines, ok := frontmatter.Split(tmpl); ok {
+		lines := strings.Split(tmpl, "\n")
+		for i := 1; i < blockLines-1; i++ {
+			if m := frontMatterVarPattern.FindStringSubmatch(lines[i]); m != nil {
+				lines[i] = m[1] + strconv.Quote(values[m[2]])
+			}
+		}
+		tmpl = strings.Join(lines, "\n")
+	}
 
-	return fmt.Sprintf(template,
-		title,
-		status,
-		now.Format("January 2, 2006"))
+	return strings.NewReplacer(
+		"{{number}}", vars.Number,
+		"{{title}}", vars.Title,
+		"{{status}}", vars.Status,
+		"{{date}}", vars.Date,
+		"{{category}}", vars.Category,
+		"{{author}}", vars.Author,
+	).Replace(tmpl)
 }
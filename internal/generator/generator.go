@@ -13,7 +13,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/euforicio/adr-demo/internal/adrfs"
 	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+	"github.com/euforicio/adr-demo/internal/gitmeta"
 	"github.com/euforicio/adr-demo/internal/markdown"
 )
 
@@ -33,6 +36,7 @@ type Generator struct {
 	stats       Stats
 	renderCache map[string]*CacheEntry // Cache for rendered pages
 	cacheMutex  sync.RWMutex           // Mutex for cache access
+	history     *gitmeta.Repository    // Git history provider (nil when disabled)
 }
 
 // Stats holds build statistics
@@ -53,19 +57,50 @@ type ADR struct {
 	FilePath    string
 	FileName    string
 	DiagramType string
-	Category    string // Category based on folder structure
+	Category    string // From front matter, category folder or content
 	CreatedAt   time.Time
 	ModifiedAt  time.Time
 	FileHash    string // SHA256 hash of the source file content
+
+	// Authorship from git history (empty when unavailable)
+	Author       string
+	Contributors []string
+
+	// Metadata from the YAML front matter block (empty when absent)
+	Date         string
+	Deciders     []string
+	Tags         []string
+	Supersedes   []string
+	SupersededBy []string
+	DependsOn    []string
+
+	// Resolved links to other ADRs
+	Relations []*Relation
 }
 
 // New creates a new generator instance
 func New(cfg *config.Config) *Generator {
-	return &Generator{
+	g := &Generator{
 		config:      cfg,
 		adrs:        make([]*ADR, 0),
 		renderCache: make(map[string]*CacheEntry),
 	}
+
+	if cfg.GitMetadata {
+		repo, err := gitmeta.Open(".")
+		if err != nil {
+			if cfg.Verbose {
+				fmt.Printf("⚠️  Git metadata unavailable, using file times: %v\n", err)
+			}
+		} else {
+			if repo.IsShallow() && cfg.Verbose {
+				fmt.Println("⚠️  Shallow clone detected, ADR creation dates may be inaccurate")
+			}
+			g.history = repo
+		}
+	}
+
+	return g
 }
 
 // Build generates the complete static site
@@ -106,10 +141,20 @@ func (g *Generator) Build() error {
 		return fmt.Errorf("failed to generate ADR pages: %w", err)
 	}
 
+	if err := g.generateRedirectPages(); err != nil {
+		return fmt.Errorf("failed to generate redirects: %w", err)
+	}
+
 	if err := g.generateSearchPage(); err != nil {
 		return fmt.Errorf("failed to generate search page: %w", err)
 	}
 
+	if len(g.config.Glossary) > 0 {
+		if err := g.generateGlossaryPage(); err != nil {
+			return fmt.Errorf("failed to generate glossary page: %w", err)
+		}
+	}
+
 	if g.config.Verbose {
 		fmt.Println("📦 Copying static assets...")
 	}
@@ -127,7 +172,8 @@ func (g *Generator) Build() error {
 	return nil
 }
 
-// loadADRs finds and parses all ADR markdown files from the flat structure
+// loadADRs finds and parses all ADR markdown files, including those in
+// category folders
 func (g *Generator) loadADRs() error {
 	adrDir := g.config.ADRDirectory
 
@@ -138,43 +184,35 @@ func (g *Generator) loadADRs() error {
 		BaseURL:       g.config.BaseURL,
 	})
 
-	// Read all files in the ADR directory (flat structure)
-	files, err := os.ReadDir(adrDir)
+	// Read all files in the ADR directory and its category folders
+	files, err := adrfs.Discover(adrDir, g.config.AllowedCategories)
 	if err != nil {
-		return fmt.Errorf("failed to read ADR directory: %w", err)
+		return err
 	}
 
 	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-
-		// Only process .md files
-		if !strings.HasSuffix(file.Name(), ".md") {
-			continue
-		}
-
-		// Skip template file
-		if file.Name() == "template.md" {
-			continue
-		}
-
 		// Parse ADR number from filename
-		if !isValidADRFilename(file.Name()) {
+		if !isValidADRFilename(file.Name) {
 			if g.config.Verbose {
-				fmt.Printf("⚠️  Skipping invalid filename: %s\n", file.Name())
+				fmt.Printf("⚠️  Skipping invalid filename: %s\n", file.RelPath())
 			}
 			continue
 		}
 
-		path := filepath.Join(adrDir, file.Name())
-		adr, err := g.parseADR(path, processor)
+		adr, err := g.parseADR(file.Path, processor)
 		if err != nil {
-			return fmt.Errorf("failed to parse ADR %s: %w", path, err)
+			return fmt.Errorf("failed to parse ADR %s: %w", file.Path, err)
 		}
 
-		// Extract category from ADR content
-		adr.Category = g.extractCategoryFromContent(adr.Content)
+		// Front matter wins, then the category folder, then the content
+		if adr.Category == "" && file.Folder != "" {
+			if category, ok := g.config.CategoryForFolder(file.Folder); ok {
+				adr.Category = category
+			}
+		}
+		if adr.Category == "" {
+			adr.Category = g.extractCategoryFromContent(adr.Content)
+		}
 
 		g.adrs = append(g.adrs, adr)
 	}
@@ -184,6 +222,9 @@ func (g *Generator) loadADRs() error {
 		return g.adrs[i].Number < g.adrs[j].Number
 	})
 
+	// Link superseded and superseding ADRs
+	g.resolveRelations()
+
 	g.stats.ADRCount = len(g.adrs)
 
 	if g.config.Verbose {
@@ -204,23 +245,29 @@ func (g *Generator) parseADR(filePath string, processor *markdown.SimpleProcesso
 	hash := sha256.Sum256(content)
 	fileHash := hex.EncodeToString(hash[:])
 
+	// Separate front matter from the markdown body
+	fm, body, err := frontmatter.Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
 	fileName := filepath.Base(filePath)
 	number := extractADRNumber(fileName)
-	title := extractTitleFromContent(string(content))
-	status := extractStatusFromContent(string(content))
+	title := extractTitleFromContent(body)
+	status := extractStatusFromContent(body)
 
 	// Process markdown to HTML
-	htmlContent, err := processor.Process(string(content))
+	htmlContent, err := processor.Process(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process markdown: %w", err)
 	}
 
 	// Count diagrams
-	diagramCount := strings.Count(string(content), "```mermaid")
+	diagramCount := strings.Count(body, "```mermaid")
 	g.stats.DiagramCount += diagramCount
 
 	// Determine diagram type
-	diagramType := detectDiagramType(string(content))
+	diagramType := detectDiagramType(body)
 
 	// Get file info
 	info, err := os.Stat(filePath)
@@ -228,11 +275,11 @@ func (g *Generator) parseADR(filePath string, processor *markdown.SimpleProcesso
 		return nil, err
 	}
 
-	return &ADR{
+	adr := &ADR{
 		Number:      number,
 		Title:       title,
 		Status:      status,
-		Content:     string(content),
+		Content:     body,
 		HTMLContent: template.HTML(htmlContent),
 		FilePath:    filePath,
 		FileName:    fileName,
@@ -240,7 +287,64 @@ func (g *Generator) parseADR(filePath string, processor *markdown.SimpleProcesso
 		CreatedAt:   info.ModTime(), // Approximation
 		ModifiedAt:  info.ModTime(),
 		FileHash:    fileHash,
-	}, nil
+	}
+
+	g.applyGitHistory(adr)
+	g.applyFrontMatter(adr, fm)
+
+	return adr, nil
+}
+
+// applyGitHistory fills dates and authors from git, keeping file times as fallback
+func (g *Generator) applyGitHistory(adr *ADR) {
+	if g.history == nil {
+		return
+	}
+
+	history, err := g.history.FileHistory(adr.FilePath)
+	if err != nil {
+		if g.config.Verbose {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+		return
+	}
+	if history == nil {
+		return // Not committed yet
+	}
+
+	adr.CreatedAt = history.CreatedAt
+	adr.ModifiedAt = history.ModifiedAt
+	adr.Author = history.Author
+	adr.Contributors = history.Contributors
+}
+
+// applyFrontMatter overrides heuristically extracted fields with front matter values
+func (g *Generator) applyFrontMatter(adr *ADR, fm *frontmatter.FrontMatter) {
+	if fm == nil {
+		return
+	}
+
+	if fm.Status != "" {
+		adr.Status = fm.Status
+	}
+	// A category that is not allowed is kept, for the allowed-category
+	// rule of "adr-gen validate" to report
+	if fm.Category != "" {
+		adr.Category = fm.Category
+		if category, ok := g.config.CategoryForFolder(fm.Category); ok {
+			adr.Category = category
+		}
+	}
+	if date, ok := fm.ParsedDate(); ok {
+		adr.CreatedAt = date
+	}
+
+	adr.Date = fm.Date
+	adr.Deciders = fm.Deciders
+	adr.Tags = fm.Tags
+	adr.Supersedes = fm.Supersedes
+	adr.SupersededBy = fm.SupersededBy
+	adr.DependsOn = fm.DependsOn
 }
 
 // GetStats returns build statistics
@@ -301,7 +405,7 @@ func extractStatusFromContent(content string) string {
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
 
-		if strings.HasPrefix(trimmed, "## Status") {
+		if trimmed == "## Status" {
 			statusSection = true
 			continue
 		}
@@ -457,8 +561,10 @@ func (g *Generator) RenderADRPage(w io.Writer, adrNumber string) error {
 		return fmt.Errorf("ADR %s not found", adrNumber)
 	}
 
-	// Create cache key based on the specific ADR hash
-	cacheKey := fmt.Sprintf("adr-%s-%s", adrNumber, targetADR.FileHash)
+	// Create cache key based on all ADR hashes, since the page shows the
+	// related ADRs (supersession banner and chain, related decisions) and
+	// the previous/next titles
+	cacheKey := g.generateADRCacheKey(adrNumber)
 
 	// Check cache first
 	if cached := g.getCachedContent(cacheKey); cached != nil {
@@ -519,6 +625,20 @@ func (g *Generator) RenderSearchPage(w io.Writer) error {
 	return g.renderPageToWriterWithCache("search.html", w, data, cacheKey)
 }
 
+// RenderGlossaryPage renders the glossary page to a writer
+func (g *Generator) RenderGlossaryPage(w io.Writer) error {
+	// The glossary lists the ADRs using each term, so it changes with them
+	cacheKey := g.generateGlossaryCacheKey()
+
+	// Check cache first
+	if cached := g.getCachedContent(cacheKey); cached != nil {
+		_, err := w.Write(cached)
+		return err
+	}
+
+	return g.renderPageToWriterWithCache("glossary.html", w, g.glossaryPageData(), cacheKey)
+}
+
 // RenderDocsPage renders the documentation page to a writer
 func (g *Generator) RenderDocsPage(w io.Writer, readmeContent string) error {
 	// Create cache key based on README content hash
@@ -619,6 +739,15 @@ func (g *Generator) generateIndexCacheKey() string {
 	return fmt.Sprintf("index-%x", hash.Sum(nil))
 }
 
+// generateADRCacheKey creates a cache key for an ADR page based on all ADR hashes
+func (g *Generator) generateADRCacheKey(number string) string {
+	hash := sha256.New()
+	for _, adr := range g.adrs {
+		hash.Write([]byte(adr.FileHash))
+	}
+	return fmt.Sprintf("adr-%s-%x", number, hash.Sum(nil))
+}
+
 // generateSearchCacheKey creates a cache key for the search page based on all ADR hashes
 func (g *Generator) generateSearchCacheKey() string {
 	hash := sha256.New()
@@ -628,6 +757,15 @@ func (g *Generator) generateSearchCacheKey() string {
 	return fmt.Sprintf("search-%x", hash.Sum(nil))
 }
 
+// generateGlossaryCacheKey creates a cache key for the glossary page based on all ADR hashes
+func (g *Generator) generateGlossaryCacheKey() string {
+	hash := sha256.New()
+	for _, adr := range g.adrs {
+		hash.Write([]byte(adr.FileHash))
+	}
+	return fmt.Sprintf("glossary-%x", hash.Sum(nil))
+}
+
 // renderPageToWriterWithCache renders a template to a writer with caching
 func (g *Generator) renderPageToWriterWithCache(templateName string, w io.Writer, data interface{}, cacheKey string) error {
 	// Parse base template and specific template individually to avoid block conflicts
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+)
+
+// GlossaryEntry is a glossary term as shown on the glossary page
+type GlossaryEntry struct {
+	Term       string
+	Anchor     string // HTML id of the entry, e.g. "event-bus"
+	Definition string
+	Variants   []string // Terms to avoid in favour of this one
+	ADRs       []*ADR   // ADRs that use the term
+}
+
+// Glossary returns the configured glossary in alphabetical order, with the
+// ADRs that mention each term
+func (g *Generator) Glossary() []GlossaryEntry {
+	entries := make([]GlossaryEntry, 0, len(g.config.Glossary))
+	for _, term := range g.config.Glossary.Terms() {
+		entry := GlossaryEntry{
+			Term:       term,
+			Anchor:     adrfs.Slugify(term),
+			Definition: g.config.Glossary[term].Definition,
+			Variants:   g.config.Glossary[term].Variants,
+		}
+
+		mention := termPattern(term)
+		for _, adr := range g.adrs {
+			if mention.MatchString(adr.Content) {
+				entry.ADRs = append(entry.ADRs, adr)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// termPattern matches term, or its plural, as whole words in any case
+func termPattern(term string) *regexp.Regexp {
+	words := strings.Fields(term)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	return regexp.MustCompile(`(?i)(?:^|[^\pL\pN_])` + strings.Join(words, `\s+`) + `(?:e?s)?(?:[^\pL\pN_]|$)`)
+}
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+)
+
+// Listing formats accepted by WriteListing
+const (
+	ListTable    = "table"
+	ListJSON     = "json"
+	ListCSV      = "csv"
+	ListMarkdown = "markdown"
+)
+
+// ListFormats lists the supported listing formats
+var ListFormats = []string{ListTable, ListJSON, ListCSV, ListMarkdown}
+
+// Sort keys accepted by Query.Sort
+const (
+	SortNumber = "number"
+	SortDate   = "date"
+	SortTitle  = "title"
+)
+
+// SortKeys lists the supported sort keys
+var SortKeys = []string{SortNumber, SortDate, SortTitle}
+
+// Query selects and orders ADRs. Empty fields do not filter.
+type Query struct {
+	Statuses   []string  // Any of these statuses, ignoring case
+	Categories []string  // Any of these categories or their folder names
+	From, To   time.Time // Inclusive range of decision dates
+	Text       string    // Substring of the title or content, ignoring case
+	Sort       string    // One of SortKeys; number when empty
+	Reverse    bool
+}
+
+// Filter returns the ADRs matching q in the requested order
+func Filter(adrs []*ADR, q Query) []*ADR {
+	text := strings.ToLower(q.Text)
+	matches := make([]*ADR, 0, len(adrs))
+	for _, adr := range adrs {
+		if len(q.Statuses) > 0 && !matchesAny(q.Statuses, func(s string) bool { return statusIs(adr.Status, s) }) {
+			continue
+		}
+		if len(q.Categories) > 0 && !matchesAny(q.Categories, func(c string) bool {
+			return strings.EqualFold(adr.Category, c) || adrfs.Slugify(adr.Category) == adrfs.Slugify(c)
+		}) {
+			continue
+		}
+		day := adr.CreatedAt.Format("2006-01-02")
+		if !q.From.IsZero() && day < q.From.Format("2006-01-02") {
+			continue
+		}
+		if !q.To.IsZero() && day > q.To.Format("2006-01-02") {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(adr.Title), text) && !strings.Contains(strings.ToLower(adr.Content), text) {
+			continue
+		}
+		matches = append(matches, adr)
+	}
+
+	var less func(a, b *ADR) bool
+	switch q.Sort {
+	case SortDate:
+		less = func(a, b *ADR) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case SortTitle:
+		less = func(a, b *ADR) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	default:
+		less = func(a, b *ADR) bool { return a.Number < b.Number }
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if q.Reverse {
+			return less(matches[j], matches[i])
+		}
+		return less(matches[i], matches[j])
+	})
+	return matches
+}
+
+// statusIs reports whether an ADR status, which may carry extra text such
+// as "Superseded by ADR-0010", is the given status
+func statusIs(adrStatus, status string) bool {
+	fields := strings.Fields(adrStatus)
+	return len(fields) > 0 && strings.EqualFold(strings.Trim(fields[0], "*_:"), status)
+}
+
+// matchesAny reports whether match holds for one of values
+func matchesAny(values []string, match func(string) bool) bool {
+	for _, value := range values {
+		if match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// listedADR is the machine-readable form of an ADR in a listing
+type listedADR struct {
+	Number   string   `json:"number"`
+	Title    string   `json:"title"`
+	Status   string   `json:"status"`
+	Category string   `json:"category"`
+	Date     string   `json:"date"`
+	Author   string   `json:"author,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	File     string   `json:"file"`
+}
+
+// listed converts an ADR for a listing
+func listed(adr *ADR) listedADR {
+	return listedADR{
+		Number:   adr.Number,
+		Title:    adr.Title,
+		Status:   adr.Status,
+		Category: adr.Category,
+		Date:     adr.CreatedAt.Format("2006-01-02"),
+		Author:   adr.Author,
+		Tags:     adr.Tags,
+		File:     filepath.ToSlash(adr.FilePath),
+	}
+}
+
+// WriteListing writes adrs as a table, JSON, CSV or a markdown table
+func WriteListing(w io.Writer, format string, adrs []*ADR) error {
+	switch format {
+	case ListTable:
+		return writeTable(w, adrs)
+	case ListJSON:
+		items := make([]listedADR, 0, len(adrs))
+		for _, adr := range adrs {
+			items = append(items, listed(adr))
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	case ListCSV:
+		return writeCSV(w, adrs)
+	case ListMarkdown:
+		return writeMarkdownTable(w, adrs)
+	default:
+		return fmt.Errorf("unknown list format %q (valid: %s)", format, strings.Join(ListFormats, ", "))
+	}
+}
+
+// writeTable writes aligned columns for the terminal
+func writeTable(w io.Writer, adrs []*ADR) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NUMBER\tSTATUS\tCATEGORY\tDATE\tTITLE")
+	for _, adr := range adrs {
+		item := listed(adr)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", item.Number, item.Status, item.Category, item.Date, item.Title)
+	}
+	return tw.Flush()
+}
+
+// writeCSV writes a header row and one row per ADR
+func writeCSV(w io.Writer, adrs []*ADR) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"number", "title", "status", "category", "date", "author", "tags", "file"}); err != nil {
+		return err
+	}
+	for _, adr := range adrs {
+		item := listed(adr)
+		row := []string{item.Number, item.Title, item.Status, item.Category, item.Date, item.Author, strings.Join(item.Tags, ";"), item.File}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeMarkdownTable writes a GitHub Flavored Markdown table linking each ADR
+func writeMarkdownTable(w io.Writer, adrs []*ADR) error {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ").Replace
+	if _, err := fmt.Fprintln(w, "| Number | Title | Status | Category | Date |\n|--------|-------|--------|----------|------|"); err != nil {
+		return err
+	}
+	for _, adr := range adrs {
+		item := listed(adr)
+		if _, err := fmt.Fprintf(w, "| %s | [%s](%s) | %s | %s | %s |\n",
+			item.Number, escape(item.Title), item.File, escape(item.Status), escape(item.Category), item.Date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
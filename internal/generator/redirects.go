@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/adrref"
+	"gopkg.in/yaml.v3"
+)
+
+// RedirectsFile records renumbered ADRs, in the ADR directory
+const RedirectsFile = "redirects.yaml"
+
+// redirectsHeader opens the redirects file
+const redirectsHeader = `# Old ADR numbers and the numbers they moved to, written by "adr-gen renumber".
+# The site keeps adr-NNNN.html for each old number as a redirect.
+`
+
+// Redirects maps old ADR numbers to the numbers they were renumbered to
+type Redirects map[string]string
+
+// LoadRedirects reads the redirects of an ADR directory; a missing file
+// means no redirects
+func LoadRedirects(adrDir string) (Redirects, error) {
+	path := filepath.Join(adrDir, RedirectsFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Redirects{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	redirects := Redirects{}
+	if err := yaml.Unmarshal(data, &redirects); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return redirects, nil
+}
+
+// Save writes the redirects sorted by old number, or removes the file when
+// there are none
+func (r Redirects) Save(adrDir string) error {
+	path := filepath.Join(adrDir, RedirectsFile)
+	if len(r) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString(redirectsHeader)
+	for _, old := range r.oldNumbers() {
+		fmt.Fprintf(&b, "%q: %q\n", old, r[old])
+	}
+	if err := adrfs.WriteFileAtomic(path, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Resolve follows redirects from number to the current number. ok is false
+// when number was never renumbered.
+func (r Redirects) Resolve(number string) (string, bool) {
+	number = adrref.NormalizeNumber(number)
+	target, ok := r[number]
+	seen := map[string]bool{number: true}
+	for ok && !seen[target] {
+		seen[target] = true
+		next, found := r[target]
+		if !found {
+			break
+		}
+		target = next
+	}
+	return target, ok
+}
+
+// oldNumbers returns the redirected numbers in order
+func (r Redirects) oldNumbers() []string {
+	numbers := make([]string, 0, len(r))
+	for old := range r {
+		numbers = append(numbers, old)
+	}
+	sort.Strings(numbers)
+	return numbers
+}
+
+// redirectPage is the page left at the URL of a renumbered ADR
+var redirectPage = template.Must(template.New("redirect").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>ADR-{{.Target.Number}}: {{.Target.Title}}</title>
+    <link rel="canonical" href="{{.URL}}">
+    <meta http-equiv="refresh" content="0; url={{.URL}}">
+</head>
+<body>
+    <p>ADR-{{.Old}} is now <a href="{{.URL}}">ADR-{{.Target.Number}}: {{.Target.Title}}</a>.</p>
+</body>
+</html>
+`))
+
+// RedirectTarget returns the ADR that an old, renumbered ADR number now
+// points to. Numbers that belong to an ADR again are not redirected.
+func (g *Generator) RedirectTarget(number string) (*ADR, bool) {
+	redirects, err := LoadRedirects(g.config.ADRDirectory)
+	if err != nil {
+		if g.config.Verbose {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+		return nil, false
+	}
+	return g.redirectTarget(redirects, number)
+}
+
+// redirectTarget resolves number against loaded redirects
+func (g *Generator) redirectTarget(redirects Redirects, number string) (*ADR, bool) {
+	number = adrref.NormalizeNumber(number)
+	for _, adr := range g.adrs {
+		if adr.Number == number {
+			return nil, false
+		}
+	}
+	target, ok := redirects.Resolve(number)
+	if !ok {
+		return nil, false
+	}
+	for _, adr := range g.adrs {
+		if adr.Number == target {
+			return adr, true
+		}
+	}
+	return nil, false
+}
+
+// generateRedirectPages writes a redirect at adr-NNNN.html for each
+// renumbered ADR
+func (g *Generator) generateRedirectPages() error {
+	redirects, err := LoadRedirects(g.config.ADRDirectory)
+	if err != nil {
+		return err
+	}
+
+	for _, old := range redirects.oldNumbers() {
+		target, ok := g.redirectTarget(redirects, old)
+		if !ok {
+			continue
+		}
+
+		outputPath := filepath.Join(g.config.OutputDirectory, fmt.Sprintf("adr-%s.html", old))
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create file %s: %w", outputPath, err)
+		}
+		err = redirectPage.Execute(file, struct {
+			Old    string
+			Target *ADR
+			URL    string
+		}{old, target, fmt.Sprintf("%s/adr-%s.html", g.config.BaseURL, target.Number)})
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to write redirect %s: %w", outputPath, err)
+		}
+		if g.config.Verbose {
+			fmt.Printf("↪️  adr-%s.html → adr-%s.html\n", old, target.Number)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/adrref"
+	"github.com/euforicio/adr-demo/internal/config"
+)
+
+// LinkTypes lists the relation types "adr-gen link" can add
+var LinkTypes = []RelationType{RelationAmends, RelationDependsOn, RelationRelatesTo, RelationConflictsWith}
+
+// relationOrder is the order relations are resolved and displayed in
+var relationOrder = []RelationType{
+	RelationSupersedes, RelationSupersededBy,
+	RelationAmends, RelationAmendedBy,
+	RelationDependsOn, RelationDependedOnBy,
+	RelationRelatesTo, RelationConflictsWith,
+}
+
+// RelatedDecisions returns the typed links other than supersession, in
+// relation order and then by ADR number
+func (a *ADR) RelatedDecisions() []*Relation {
+	var related []*Relation
+	for _, relType := range relationOrder {
+		if relType == RelationSupersedes || relType == RelationSupersededBy {
+			continue
+		}
+		var group []*Relation
+		for _, rel := range a.Relations {
+			if rel.Type == relType {
+				group = append(group, rel)
+			}
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].Target.Number < group[j].Target.Number })
+		related = append(related, group...)
+	}
+	return related
+}
+
+// LinkResult describes the entries added by Link
+type LinkResult struct {
+	From, To *ADR // The ADRs as loaded before linking
+	Type     RelationType
+	Added    []string // Paths of the ADRs that gained an entry
+}
+
+// Link records a typed relation from ADR from to ADR to, and the inverse
+// relation back, as entries under Related Decisions in both files. The
+// section is created at the end of an ADR that has none. Entries that are
+// already present are kept; linking twice is an error. The second file is
+// written only after the first, which is restored if the second fails.
+func Link(project *config.Config, from, to int, relType RelationType) (*LinkResult, error) {
+	if !isLinkType(relType) {
+		return nil, fmt.Errorf("unknown link type %q (valid: %s)", relType, joinRelationTypes(LinkTypes))
+	}
+	if from == to {
+		return nil, fmt.Errorf("cannot link ADR %04d to itself", from)
+	}
+
+	// Load the log without git history; only titles and paths are needed
+	lookup := *project
+	lookup.GitMetadata = false
+	g := New(&lookup)
+	if err := g.LoadADRsOnly(); err != nil {
+		return nil, err
+	}
+	source, err := loadedADR(g, project.ADRDirectory, from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := loadedADR(g, project.ADRDirectory, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LinkResult{From: source, To: target, Type: relType}
+	sides := []struct {
+		adr, other *ADR
+		relType    RelationType
+	}{
+		{source, target, relType},
+		{target, source, relType.Inverse()},
+	}
+
+	var rewrites []fileRewrite
+	for _, side := range sides {
+		data, err := os.ReadFile(side.adr.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", side.adr.FilePath, err)
+		}
+		if hasRef(adrref.Related(string(data))[side.relType], side.other.Number) {
+			continue
+		}
+		link, err := relativeLink(filepath.Dir(side.adr.FilePath), side.other.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		entry := fmt.Sprintf("- %s [ADR-%s: %s](%s)", side.relType.Label(), side.other.Number, side.other.Title, link)
+		rewrites = append(rewrites, fileRewrite{
+			path:     side.adr.FilePath,
+			original: data,
+			updated:  []byte(AppendRelatedEntry(string(data), entry)),
+		})
+	}
+	if len(rewrites) == 0 {
+		return nil, fmt.Errorf("ADR-%s already %s ADR-%s", source.Number, strings.ToLower(relType.Label()), target.Number)
+	}
+
+	if err := writeRewrites(rewrites); err != nil {
+		return nil, err
+	}
+	for _, rw := range rewrites {
+		result.Added = append(result.Added, rw.path)
+	}
+	return result, nil
+}
+
+// loadedADR returns the loaded ADR with the given number
+func loadedADR(g *Generator, dir string, number int) (*ADR, error) {
+	file, err := adrfs.Find(dir, g.config.AllowedCategories, number)
+	if err != nil {
+		return nil, err
+	}
+	for _, adr := range g.GetADRs() {
+		if adr.FilePath == file.Path {
+			return adr, nil
+		}
+	}
+	return nil, fmt.Errorf("ADR %04d could not be loaded from %s", number, file.Path)
+}
+
+// AppendRelatedEntry adds entry as the last item of the Related Decisions
+// section, creating the section at the end of the ADR (before a closing
+// --- footer) when there is none
+func AppendRelatedEntry(content, entry string) string {
+	lines := strings.Split(content, "\n")
+
+	section := -1
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if !inCode && strings.HasPrefix(trimmed, "#") && adrref.IsRelatedHeading(trimmed) {
+			section = i
+			break
+		}
+	}
+
+	if section >= 0 {
+		// After the last non-blank line before the next heading
+		end := len(lines)
+		for i := section + 1; i < len(lines); i++ {
+			if strings.HasPrefix(strings.TrimSpace(lines[i]), "#") {
+				end = i
+				break
+			}
+		}
+		if end == len(lines) {
+			end = footerStart(lines)
+		}
+		for end > section+1 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		if end == section+1 {
+			return strings.Join(insertBlock(lines, end, []string{entry}), "\n")
+		}
+		return strings.Join(insertAt(lines, end, []string{entry}), "\n")
+	}
+
+	at := footerStart(lines)
+	if at > 0 && at == len(lines) && strings.TrimSpace(lines[at-1]) == "" {
+		at-- // Keep the trailing newline last
+	}
+	return strings.Join(insertBlock(lines, at, []string{"## " + adrref.RelatedHeading, "", entry}), "\n")
+}
+
+// isLinkType reports whether t can be added by "adr-gen link"
+func isLinkType(t RelationType) bool {
+	for _, linkType := range LinkTypes {
+		if t == linkType {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRelationTypes lists relation types for messages
+func joinRelationTypes(types []RelationType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
+
+// hasRef reports whether refs mention the ADR number
+func hasRef(refs []string, number string) bool {
+	for _, ref := range refs {
+		if adrref.NormalizeNumber(ref) == number {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/euforicio/adr-demo/internal/adrref"
+)
+
+// RelationType identifies the kind of relationship between two ADRs
+type RelationType = adrref.RelationType
+
+const (
+	RelationSupersedes   = adrref.Supersedes
+	RelationSupersededBy = adrref.SupersededBy
+
+	// Typed links written under Related Decisions by "adr-gen link"
+	RelationAmends        = adrref.Amends
+	RelationAmendedBy     = adrref.AmendedBy
+	RelationDependsOn     = adrref.DependsOn
+	RelationDependedOnBy  = adrref.DependedOnBy
+	RelationRelatesTo     = adrref.RelatesTo
+	RelationConflictsWith = adrref.ConflictsWith
+)
+
+// Relation is a typed, resolved link from one ADR to another
+type Relation struct {
+	Type     RelationType
+	Target   *ADR
+	Inferred bool // true when derived from the inverse link on the target
+}
+
+// resolveRelations turns supersession references and Related Decisions
+// entries into typed relations and infers the inverse link on the target ADR
+func (g *Generator) resolveRelations() {
+	byNumber := make(map[string]*ADR, len(g.adrs))
+	for _, adr := range g.adrs {
+		byNumber[adr.Number] = adr
+		adr.Relations = nil
+	}
+
+	// Explicit references from front matter, prose and Related Decisions
+	for _, adr := range g.adrs {
+		refs := adrref.Related(adr.Content)
+		prose := adrref.Supersessions(adr.Content)
+		refs[RelationSupersedes] = append(append([]string{}, adr.Supersedes...), prose[RelationSupersedes]...)
+		refs[RelationSupersededBy] = append(append([]string{}, adr.SupersededBy...), prose[RelationSupersededBy]...)
+		refs[RelationDependsOn] = append(append([]string{}, adr.DependsOn...), refs[RelationDependsOn]...)
+
+		for _, relType := range relationOrder {
+			for _, ref := range refs[relType] {
+				target, ok := byNumber[adrref.NormalizeNumber(ref)]
+				if !ok || target == adr {
+					if g.config.Verbose {
+						fmt.Printf("⚠️  ADR-%s: unresolved %s reference %q\n", adr.Number, relType, ref)
+					}
+					continue
+				}
+				adr.addRelation(relType, target, false)
+			}
+		}
+	}
+
+	// Inverse links
+	for _, adr := range g.adrs {
+		for _, rel := range adr.Relations {
+			if rel.Inferred {
+				continue
+			}
+			rel.Target.addRelation(rel.Type.Inverse(), adr, true)
+		}
+	}
+
+	// Keep the plain number lists in sync for templates and the search index
+	for _, adr := range g.adrs {
+		adr.Supersedes = relationNumbers(adr.RelatedBy(RelationSupersedes))
+		adr.SupersededBy = relationNumbers(adr.RelatedBy(RelationSupersededBy))
+		adr.DependsOn = relationNumbers(adr.RelatedBy(RelationDependsOn))
+	}
+}
+
+// addRelation records a relation unless an equivalent one already exists
+func (a *ADR) addRelation(relType RelationType, target *ADR, inferred bool) {
+	for _, rel := range a.Relations {
+		if rel.Type == relType && rel.Target == target {
+			return
+		}
+	}
+	a.Relations = append(a.Relations, &Relation{Type: relType, Target: target, Inferred: inferred})
+}
+
+// RelatedBy returns the ADRs linked to this one by the given relation type
+func (a *ADR) RelatedBy(relType RelationType) []*ADR {
+	var related []*ADR
+	for _, rel := range a.Relations {
+		if rel.Type == relType {
+			related = append(related, rel.Target)
+		}
+	}
+	return related
+}
+
+// SupersedingADR returns the ADR that replaces this one, or nil
+func (a *ADR) SupersedingADR() *ADR {
+	if related := a.RelatedBy(RelationSupersededBy); len(related) > 0 {
+		return related[0]
+	}
+	return nil
+}
+
+// SupersededADRs returns the ADRs replaced by this one
+func (a *ADR) SupersededADRs() []*ADR {
+	return a.RelatedBy(RelationSupersedes)
+}
+
+// Head follows the superseded-by chain and returns the current decision
+func (a *ADR) Head() *ADR {
+	seen := map[*ADR]bool{a: true}
+	current := a
+	for {
+		next := current.SupersedingADR()
+		if next == nil || seen[next] {
+			return current
+		}
+		seen[next] = true
+		current = next
+	}
+}
+
+// Chain returns every ADR in this ADR's supersession history, oldest first,
+// ending with the current head
+func (a *ADR) Chain() []*ADR {
+	head := a.Head()
+	chain := []*ADR{head}
+	seen := map[*ADR]bool{head: true}
+
+	for i := 0; i < len(chain); i++ {
+		for _, prev := range chain[i].SupersededADRs() {
+			if !seen[prev] {
+				seen[prev] = true
+				chain = append(chain, prev)
+			}
+		}
+	}
+
+	// Reverse so the oldest decision comes first
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// collapseChains returns only the head of each supersession chain, keeping
+// the original order of the heads
+func collapseChains(adrs []*ADR) []*ADR {
+	collapsed := make([]*ADR, 0, len(adrs))
+	for _, adr := range adrs {
+		if adr.Head() == adr {
+			collapsed = append(collapsed, adr)
+		}
+	}
+	return collapsed
+}
+
+// relationNumbers lists the numbers of the given ADRs
+func relationNumbers(adrs []*ADR) []string {
+	if len(adrs) == 0 {
+		return nil
+	}
+	numbers := make([]string, len(adrs))
+	for i, adr := range adrs {
+		numbers[i] = adr.Number
+	}
+	return numbers
+}
@@ -0,0 +1,416 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/adrref"
+	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+)
+
+var (
+	// renumberPattern matches a markdown link or an ADR-NNNN mention
+	renumberPattern = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)|\bADR-(\d{4})\b`)
+
+	// mentionPattern matches an ADR-NNNN mention
+	mentionPattern = regexp.MustCompile(`\bADR-(\d{4})\b`)
+
+	// relationKeyPattern matches the front matter keys that reference ADRs
+	relationKeyPattern = regexp.MustCompile(`^(supersedes|superseded_by|depends_on):`)
+
+	// frontMatterRefPattern matches an ADR reference in a front matter value
+	frontMatterRefPattern = regexp.MustCompile(`(?i)\b(ADR-)?(\d{1,4})\b`)
+)
+
+// Move is an ADR file that gets a new number
+type Move struct {
+	From, To  string // File paths before and after the move
+	OldNumber string
+	NewNumber string
+}
+
+// Renumbering describes the changes made, or planned, by a renumber
+type Renumbering struct {
+	Moves     []Move
+	Updated   []string          // ADRs whose links or mentions were rewritten
+	Ambiguous []string          // Numbers shared by several ADRs, whose mentions were left alone
+	Redirects map[string]string // Old numbers that now redirect, to their new number
+	// Old numbers of moved ADRs that another ADR takes over, so links and
+	// bookmarks outside the log that use them reach that ADR instead
+	Reassigned []string
+	// Redirects from an earlier renumber that were dropped because their
+	// number belongs to an ADR again, to the number they pointed to
+	Dropped map[string]string
+}
+
+// RenumberOptions controls a renumber
+type RenumberOptions struct {
+	DryRun bool // Plan the changes without writing anything
+}
+
+// Renumber gives one ADR a new number. from is an ADR number or the path
+// of an ADR file; when several files share the number, the newest one by
+// git history moves, as the validator suggests for duplicates.
+func Renumber(project *config.Config, from string, to int, opts RenumberOptions) (*Renumbering, error) {
+	files, err := adrfs.Discover(project.ADRDirectory, project.AllowedCategories)
+	if err != nil {
+		return nil, err
+	}
+	if to <= 0 || to > 9999 {
+		return nil, fmt.Errorf("invalid ADR number %d", to)
+	}
+
+	file, err := renumberSource(files, from)
+	if err != nil {
+		return nil, err
+	}
+	if file.Number() == to {
+		return nil, fmt.Errorf("%s already has number %04d", file.RelPath(), to)
+	}
+	return renumber(project, files, map[string]int{file.Path: to}, opts)
+}
+
+// Compact renumbers the log so that numbers run without gaps from the
+// lowest one, giving files that share a number their own numbers. Older
+// files keep the lower numbers.
+func Compact(project *config.Config, opts RenumberOptions) (*Renumbering, error) {
+	files, err := adrfs.Discover(project.ADRDirectory, project.AllowedCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	byNumber := make(map[int][]adrfs.File)
+	var numbers []int
+	for _, file := range files {
+		num := file.Number()
+		if num < 0 {
+			continue
+		}
+		if len(byNumber[num]) == 0 {
+			numbers = append(numbers, num)
+		}
+		byNumber[num] = append(byNumber[num], file)
+	}
+	sort.Ints(numbers)
+
+	moves := make(map[string]int)
+	if len(numbers) > 0 {
+		next := numbers[0]
+		for _, num := range numbers {
+			group := byNumber[num]
+			adrfs.SortByAge(group)
+			for _, file := range group {
+				if file.Number() != next {
+					moves[file.Path] = next
+				}
+				next++
+			}
+		}
+	}
+	return renumber(project, files, moves, opts)
+}
+
+// renumberSource finds the file named by a number or path argument
+func renumberSource(files []adrfs.File, from string) (adrfs.File, error) {
+	if strings.HasSuffix(from, ".md") {
+		target, err := filepath.Abs(from)
+		if err != nil {
+			return adrfs.File{}, err
+		}
+		for _, file := range files {
+			if abs, err := filepath.Abs(file.Path); err == nil && abs == target {
+				return file, nil
+			}
+		}
+		return adrfs.File{}, fmt.Errorf("%s is not an ADR in the decision log", from)
+	}
+
+	number, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(from), "ADR-"))
+	if err != nil || number <= 0 {
+		return adrfs.File{}, fmt.Errorf("invalid ADR number or path %q", from)
+	}
+	var group []adrfs.File
+	for _, file := range files {
+		if file.Number() == number {
+			group = append(group, file)
+		}
+	}
+	if len(group) == 0 {
+		return adrfs.File{}, fmt.Errorf("ADR %04d not found", number)
+	}
+	adrfs.SortByAge(group)
+	return group[len(group)-1], nil
+}
+
+// renumber moves files to new numbers, rewrites links and mentions across
+// the log and records redirects for numbers no ADR uses any more. moves
+// maps file paths to their new numbers.
+func renumber(project *config.Config, files []adrfs.File, moves map[string]int, opts RenumberOptions) (*Renumbering, error) {
+	result := &Renumbering{Redirects: map[string]string{}, Dropped: map[string]string{}}
+	if len(moves) == 0 {
+		return result, nil
+	}
+
+	// Numbers before and after the moves
+	owners := make(map[string]int)
+	final := make(map[string]string)
+	for _, file := range files {
+		if file.Number() < 0 {
+			continue
+		}
+		owners[fmt.Sprintf("%04d", file.Number())]++
+		number := fmt.Sprintf("%04d", file.Number())
+		if to, ok := moves[file.Path]; ok {
+			number = fmt.Sprintf("%04d", to)
+		}
+		if other, taken := final[number]; taken {
+			return nil, fmt.Errorf("ADR %s would be used by both %s and %s", number, other, file.RelPath())
+		}
+		final[number] = file.RelPath()
+	}
+
+	// What to rewrite: links by the file they point to, mentions by number
+	links := make(map[string]Move)
+	mentions := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	for _, file := range files {
+		to, ok := moves[file.Path]
+		if !ok {
+			continue
+		}
+		move := Move{
+			From:      file.Path,
+			To:        filepath.Join(filepath.Dir(file.Path), fmt.Sprintf("%04d%s", to, file.Name[4:])),
+			OldNumber: fmt.Sprintf("%04d", file.Number()),
+			NewNumber: fmt.Sprintf("%04d", to),
+		}
+		result.Moves = append(result.Moves, move)
+
+		abs, err := filepath.Abs(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		links[abs] = move
+		if owners[move.OldNumber] > 1 {
+			ambiguous[move.OldNumber] = true
+		} else {
+			mentions[move.OldNumber] = move.NewNumber
+		}
+		if _, used := final[move.OldNumber]; used {
+			result.Reassigned = append(result.Reassigned, move.OldNumber)
+		} else {
+			result.Redirects[move.OldNumber] = move.NewNumber
+		}
+	}
+	sort.Slice(result.Moves, func(i, j int) bool { return result.Moves[i].OldNumber < result.Moves[j].OldNumber })
+	sort.Strings(result.Reassigned)
+	for number := range ambiguous {
+		result.Ambiguous = append(result.Ambiguous, number)
+	}
+	sort.Strings(result.Ambiguous)
+
+	// Rewrite every ADR in memory first
+	rewritten := make(map[string][]byte)
+	for _, file := range files {
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Path, err)
+		}
+
+		// A moved file's mentions of its own old number refer to itself,
+		// even when another file shares the number
+		own := mentions
+		if to, ok := moves[file.Path]; ok && ambiguous[fmt.Sprintf("%04d", file.Number())] {
+			own = withMention(mentions, fmt.Sprintf("%04d", file.Number()), fmt.Sprintf("%04d", to))
+		}
+
+		content := rewriteReferences(string(data), filepath.Dir(file.Path), links, own)
+		if content != string(data) {
+			rewritten[file.Path] = []byte(content)
+			result.Updated = append(result.Updated, file.Path)
+		}
+	}
+
+	redirects, err := LoadRedirects(project.ADRDirectory)
+	if err != nil {
+		return nil, err
+	}
+	for old, to := range result.Redirects {
+		redirects[old] = to
+	}
+	for old, to := range redirects {
+		// Earlier redirects follow the ADR to its new number
+		if next, ok := result.Redirects[to]; ok {
+			redirects[old] = next
+		}
+		// A number that belongs to an ADR again serves that ADR
+		if _, used := final[old]; used {
+			result.Dropped[old] = redirects[old]
+			delete(redirects, old)
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for path, data := range rewritten {
+		if err := adrfs.WriteFileAtomic(path, data); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	if err := moveFiles(result.Moves); err != nil {
+		return nil, err
+	}
+	if err := redirects.Save(project.ADRDirectory); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// moveFiles renames files through temporary names, so that numbers can
+// swap or shift, and undoes the renames when one fails
+func moveFiles(moves []Move) error {
+	var done [][2]string
+	undo := func() {
+		for i := len(done) - 1; i >= 0; i-- {
+			os.Rename(done[i][1], done[i][0])
+		}
+	}
+	rename := func(from, to string) error {
+		if err := os.Rename(from, to); err != nil {
+			undo()
+			return fmt.Errorf("failed to move %s: %w", from, err)
+		}
+		done = append(done, [2]string{from, to})
+		return nil
+	}
+
+	for _, move := range moves {
+		if err := rename(move.From, move.From+".renumber"); err != nil {
+			return err
+		}
+	}
+	for _, move := range moves {
+		if _, err := os.Stat(move.To); err == nil {
+			undo()
+			return fmt.Errorf("cannot move to %s: the file already exists", move.To)
+		}
+		if err := rename(move.From+".renumber", move.To); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteReferences points links to moved files at their new names and
+// replaces ADR-NNNN mentions and front matter references by their new
+// numbers. Code blocks are left alone.
+func rewriteReferences(content, dir string, links map[string]Move, mentions map[string]string) string {
+	lines := strings.Split(content, "\n")
+
+	start := 0
+	if _, _, blockLines, ok := frontmatter.Split(content); ok {
+		inRelation := false
+		for i := 1; i < blockLines-1 && i < len(lines); i++ {
+			line := lines[i]
+			if relationKeyPattern.MatchString(line) {
+				inRelation = true
+			} else if !strings.HasPrefix(strings.TrimSpace(line), "-") {
+				inRelation = false
+			}
+			if inRelation {
+				lines[i] = rewriteFrontMatterRefs(line, mentions)
+			}
+		}
+		start = blockLines
+	}
+
+	inCode := false
+	for i := start; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		lines[i] = renumberPattern.ReplaceAllStringFunc(lines[i], func(match string) string {
+			m := renumberPattern.FindStringSubmatch(match)
+			if m[3] != "" {
+				if to, ok := mentions[m[3]]; ok {
+					return "ADR-" + to
+				}
+				return match
+			}
+
+			text, dest := m[1], m[2]
+			target, fragment := dest, ""
+			if at := strings.Index(dest, "#"); at >= 0 {
+				target, fragment = dest[:at], dest[at:]
+			}
+			if target != "" && !strings.Contains(target, "://") {
+				if abs, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(target))); err == nil {
+					if move, ok := links[abs]; ok {
+						dest = path.Join(path.Dir(target), filepath.Base(move.To)) + fragment
+						text = rewriteMentions(text, withMention(mentions, move.OldNumber, move.NewNumber))
+						return "[" + text + "](" + dest + ")"
+					}
+				}
+			}
+			return "[" + rewriteMentions(text, mentions) + "](" + dest + ")"
+		})
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// rewriteMentions replaces ADR-NNNN mentions by their new numbers
+func rewriteMentions(text string, mentions map[string]string) string {
+	return mentionPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if to, ok := mentions[match[4:]]; ok {
+			return "ADR-" + to
+		}
+		return match
+	})
+}
+
+// withMention returns a copy of mentions that also maps old to new
+func withMention(mentions map[string]string, old, new string) map[string]string {
+	extended := make(map[string]string, len(mentions)+1)
+	for k, v := range mentions {
+		extended[k] = v
+	}
+	extended[old] = new
+	return extended
+}
+
+// rewriteFrontMatterRefs replaces ADR numbers in a front matter value such
+// as `supersedes: ["0008", ADR-0009, 7]`, keeping their spelling
+func rewriteFrontMatterRefs(line string, mentions map[string]string) string {
+	key, value := "", line
+	if loc := relationKeyPattern.FindStringIndex(line); loc != nil {
+		key, value = line[:loc[1]], line[loc[1]:]
+	}
+
+	return key + frontMatterRefPattern.ReplaceAllStringFunc(value, func(match string) string {
+		m := frontMatterRefPattern.FindStringSubmatch(match)
+		to, ok := mentions[adrref.NormalizeNumber(m[2])]
+		if !ok {
+			return match
+		}
+		if len(m[2]) < 4 {
+			n, _ := strconv.Atoi(to)
+			to = strconv.Itoa(n)
+		}
+		return m[1] + to
+	})
+}
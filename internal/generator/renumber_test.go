@@ -0,0 +1,200 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewriteReferences(t *testing.T) {
+	dir := t.TempDir()
+	moved := Move{
+		From:      filepath.Join(dir, "0012-use-grpc.md"),
+		To:        filepath.Join(dir, "0013-use-grpc.md"),
+		OldNumber: "0012",
+		NewNumber: "0013",
+	}
+	nested := Move{
+		From:      filepath.Join(dir, "backend", "0004-use-kafka.md"),
+		To:        filepath.Join(dir, "backend", "0005-use-kafka.md"),
+		OldNumber: "0004",
+		NewNumber: "0005",
+	}
+	links := map[string]Move{moved.From: moved, nested.From: nested}
+
+	tests := []struct {
+		name     string
+		content  string
+		mentions map[string]string
+		want     string
+	}{
+		{
+			name:     "link and mention",
+			content:  "Supersedes [ADR-0012: Use gRPC](0012-use-grpc.md), see ADR-0012.",
+			mentions: map[string]string{"0012": "0013"},
+			want:     "Supersedes [ADR-0013: Use gRPC](0013-use-grpc.md), see ADR-0013.",
+		},
+		{
+			name:     "link with fragment",
+			content:  "[Consequences](0012-use-grpc.md#consequences)",
+			mentions: map[string]string{"0012": "0013"},
+			want:     "[Consequences](0013-use-grpc.md#consequences)",
+		},
+		{
+			name:     "link into a category folder",
+			content:  "Depends on [ADR-0004](backend/0004-use-kafka.md)",
+			mentions: map[string]string{"0004": "0005"},
+			want:     "Depends on [ADR-0005](backend/0005-use-kafka.md)",
+		},
+		{
+			name:     "code fence left alone",
+			content:  "ADR-0012\n```\nADR-0012 [x](0012-use-grpc.md)\n```\nADR-0012",
+			mentions: map[string]string{"0012": "0013"},
+			want:     "ADR-0013\n```\nADR-0012 [x](0012-use-grpc.md)\n```\nADR-0013",
+		},
+		{
+			name:     "shared number keeps mentions but follows the moved link",
+			content:  "[ADR-0012](0012-use-grpc.md) and [ADR-0012](0012-use-rest.md), ADR-0012",
+			mentions: map[string]string{},
+			want:     "[ADR-0013](0013-use-grpc.md) and [ADR-0012](0012-use-rest.md), ADR-0012",
+		},
+		{
+			name:     "external links untouched",
+			content:  "[spec](https://example.com/0012-use-grpc.md)",
+			mentions: map[string]string{"0012": "0013"},
+			want:     "[spec](https://example.com/0012-use-grpc.md)",
+		},
+		{
+			name:     "front matter references keep their spelling",
+			content:  "---\nsupersedes: [\"0012\", ADR-0004, 12]\ndepends_on:\n  - 4\ntitle: ADR-0012 notes\n---\n\n# Title",
+			mentions: map[string]string{"0012": "0013", "0004": "0005"},
+			want:     "---\nsupersedes: [\"0013\", ADR-0005, 13]\ndepends_on:\n  - 5\ntitle: ADR-0012 notes\n---\n\n# Title",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rewriteReferences(tt.content, dir, links, tt.mentions)
+			if got != tt.want {
+				t.Errorf("rewriteReferences() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteFrontMatterRefs(t *testing.T) {
+	mentions := map[string]string{"0007": "0008", "0012": "0010"}
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`supersedes: ["0007"]`, `supersedes: ["0008"]`},
+		{`supersedes: ADR-0007`, `supersedes: ADR-0008`},
+		{`superseded_by: 7`, `superseded_by: 8`},
+		{`depends_on: [7, "0012", 3]`, `depends_on: [8, "0010", 3]`},
+		{`  - adr-0012`, `  - adr-0010`},
+		{`supersedes: ["0070"]`, `supersedes: ["0070"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := rewriteFrontMatterRefs(tt.line, mentions); got != tt.want {
+				t.Errorf("rewriteFrontMatterRefs(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string // File name to content before the moves
+		moves [][2]string       // From and to file names
+		want  map[string]string // File name to content after the moves
+	}{
+		{
+			name:  "swap",
+			files: map[string]string{"0001-a.md": "a", "0002-b.md": "b"},
+			moves: [][2]string{{"0001-a.md", "0002-a.md"}, {"0002-b.md", "0001-b.md"}},
+			want:  map[string]string{"0002-a.md": "a", "0001-b.md": "b"},
+		},
+		{
+			name:  "shift down into a gap",
+			files: map[string]string{"0002-b.md": "b", "0003-c.md": "c"},
+			moves: [][2]string{{"0002-b.md", "0001-b.md"}, {"0003-c.md", "0002-c.md"}},
+			want:  map[string]string{"0001-b.md": "b", "0002-c.md": "c"},
+		},
+		{
+			name:  "shift up over the next file",
+			files: map[string]string{"0001-a.md": "a", "0002-a.md": "a2"},
+			moves: [][2]string{{"0001-a.md", "0002-a.md"}, {"0002-a.md", "0003-a.md"}},
+			want:  map[string]string{"0002-a.md": "a", "0003-a.md": "a2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeTestFile(t, filepath.Join(dir, name), content)
+			}
+			var moves []Move
+			for _, m := range tt.moves {
+				moves = append(moves, Move{From: filepath.Join(dir, m[0]), To: filepath.Join(dir, m[1])})
+			}
+
+			if err := moveFiles(moves); err != nil {
+				t.Fatalf("moveFiles() error = %v", err)
+			}
+			assertDirContents(t, dir, tt.want)
+		})
+	}
+}
+
+func TestMoveFilesUndoesOnConflict(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "0001-a.md"), "a")
+	writeTestFile(t, filepath.Join(dir, "0002-b.md"), "b")
+
+	// 0002-b.md does not move, so 0001-a.md cannot take its name
+	moves := []Move{{From: filepath.Join(dir, "0001-a.md"), To: filepath.Join(dir, "0002-b.md")}}
+	if err := moveFiles(moves); err == nil {
+		t.Fatal("moveFiles() error = nil, want an error for an existing target")
+	}
+	assertDirContents(t, dir, map[string]string{"0001-a.md": "a", "0002-b.md": "b"})
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertDirContents(t *testing.T, dir string, want map[string]string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(want) {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Fatalf("files = %v, want %d files", names, len(want))
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("missing %s: %v", name, err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", name, data, content)
+		}
+	}
+}
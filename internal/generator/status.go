@@ -0,0 +1,390 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/adrref"
+	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+)
+
+// StatusHistoryHeading is the section that records status changes
+const StatusHistoryHeading = "Status History"
+
+// frontMatterStatusPattern matches the status key of a front matter block
+var frontMatterStatusPattern = regexp.MustCompile(`^(status:\s*)(["']?)([^"'#]*?)(["']?)(\s*(?:#.*)?)$`)
+
+// StatusChange describes a status change of an ADR
+type StatusChange struct {
+	Path string // ADR file that was rewritten
+	From string
+	To   string
+
+	// Superseded lists the ADRs that an accepted replacement moved to
+	// Superseded
+	Superseded []*StatusChange
+}
+
+// StatusUpdate requests a status change for ChangeStatus
+type StatusUpdate struct {
+	Number int
+	Status string
+	Author string // Recorded in the status history when set
+	Reason string // Recorded in the status history when set
+	Date   time.Time
+}
+
+// ChangeStatus moves an ADR to a new status. The move must be allowed by
+// status_transitions. The status is rewritten in the front matter and the
+// Status section, and a dated entry is appended to the Status History
+// section, which is created after the Status section when missing.
+//
+// Accepting an ADR that supersedes others, such as one created by
+// "adr-gen supersede" as a proposal, also moves those ADRs to Superseded,
+// recording the same reason in their history.
+// The files are written together and restored if any write fails.
+func ChangeStatus(project *config.Config, update StatusUpdate) (*StatusChange, error) {
+	file, err := adrfs.Find(project.ADRDirectory, project.AllowedCategories, update.Number)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Path, err)
+	}
+
+	updated, change, err := applyStatus(project, string(data), update)
+	if err != nil {
+		return nil, err
+	}
+	change.Path = file.Path
+
+	rewrites := []fileRewrite{{path: file.Path, original: data, updated: []byte(updated)}}
+	if strings.EqualFold(change.To, "Accepted") {
+		superseded, err := supersedeReplaced(project, string(data), update)
+		if err != nil {
+			return nil, err
+		}
+		for _, rw := range superseded {
+			rewrites = append(rewrites, rw.fileRewrite)
+			change.Superseded = append(change.Superseded, rw.change)
+		}
+	}
+
+	if err := writeRewrites(rewrites); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// supersession is the rewrite of an ADR that an accepted replacement
+// supersedes
+type supersession struct {
+	fileRewrite
+	change *StatusChange
+}
+
+// supersedeReplaced prepares the ADRs that content supersedes, through its
+// front matter or a "Supersedes" line, for the move to Superseded, with the
+// update's reason. ADRs that are already Superseded are left alone.
+func supersedeReplaced(project *config.Config, content string, update StatusUpdate) ([]supersession, error) {
+	refs := adrref.Supersessions(content)[RelationSupersedes]
+	if fm, _, err := frontmatter.Parse(content); err == nil && fm != nil {
+		refs = append(append([]string{}, fm.Supersedes...), refs...)
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	// Load the log without git history; only titles and paths are needed
+	lookup := *project
+	lookup.GitMetadata = false
+	g := New(&lookup)
+	if err := g.LoadADRsOnly(); err != nil {
+		return nil, err
+	}
+	replacement, err := loadedADR(g, project.ADRDirectory, update.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []supersession
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		number := adrref.NormalizeNumber(ref)
+		if number == "" || seen[number] || number == replacement.Number {
+			continue
+		}
+		seen[number] = true
+
+		n, _ := strconv.Atoi(number)
+		old, err := loadedADR(g, project.ADRDirectory, n)
+		if err != nil {
+			return nil, fmt.Errorf("ADR-%s supersedes ADR-%s: %w", replacement.Number, number, err)
+		}
+		if strings.EqualFold(adrref.StatusWord(old.Status), "Superseded") {
+			continue
+		}
+
+		data, err := os.ReadFile(old.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", old.FilePath, err)
+		}
+		content, change, err := supersedeContent(project, old, replacement.Number, replacement.Title, replacement.FilePath, update.Author, update.Reason, update.Date)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, supersession{
+			fileRewrite: fileRewrite{path: old.FilePath, original: data, updated: []byte(content)},
+			change:      change,
+		})
+	}
+	return result, nil
+}
+
+// fileRewrite is a pending rewrite of an ADR file
+type fileRewrite struct {
+	path              string
+	original, updated []byte
+}
+
+// writeRewrites writes the files in order and restores the ones already
+// written when a write fails
+func writeRewrites(rewrites []fileRewrite) error {
+	for i, rw := range rewrites {
+		if err := adrfs.WriteFileAtomic(rw.path, rw.updated); err != nil {
+			for _, done := range rewrites[:i] {
+				adrfs.WriteFileAtomic(done.path, done.original)
+			}
+			return fmt.Errorf("failed to write %s: %w", rw.path, err)
+		}
+	}
+	return nil
+}
+
+// applyStatus checks a status change against status_transitions and
+// returns the rewritten content with its status history entry
+func applyStatus(project *config.Config, content string, update StatusUpdate) (string, *StatusChange, error) {
+	to, ok := canonicalStatus(project, update.Status)
+	if !ok {
+		return "", nil, fmt.Errorf("unknown status %q (allowed: %s)", update.Status, strings.Join(project.AllowedStatuses, ", "))
+	}
+
+	from := adrref.StatusWord(CurrentStatus(content))
+	if strings.EqualFold(from, to) {
+		return "", nil, fmt.Errorf("ADR %04d is already %s", update.Number, to)
+	}
+	if from != "" && !project.CanTransition(from, to) {
+		return "", nil, fmt.Errorf("status_transitions does not allow %s → %s (allowed from %s: %s)", from, to, from, allowedNext(project, from))
+	}
+
+	updated, ok := SetStatus(content, to)
+	if !ok {
+		return "", nil, fmt.Errorf("ADR %04d has no status in its front matter or ## Status section", update.Number)
+	}
+
+	entry := fmt.Sprintf("- %s: %s → %s", update.Date.Format("2006-01-02"), orUnknown(from), to)
+	if update.Author != "" {
+		entry += fmt.Sprintf(" (%s)", update.Author)
+	}
+	if update.Reason != "" {
+		entry += ": " + update.Reason
+	}
+	return AppendStatusHistory(updated, entry), &StatusChange{From: from, To: to}, nil
+}
+
+// allowedNext describes the statuses status_transitions allows after from
+func allowedNext(project *config.Config, from string) string {
+	next, _ := project.NextStatuses(from)
+	if len(next) == 0 {
+		return "none"
+	}
+	return strings.Join(next, ", ")
+}
+
+// canonicalStatus returns the configured spelling of status
+func canonicalStatus(project *config.Config, status string) (string, bool) {
+	for _, allowed := range project.AllowedStatuses {
+		if strings.EqualFold(allowed, status) {
+			return allowed, true
+		}
+	}
+	return "", false
+}
+
+// orUnknown labels a missing status in the history
+func orUnknown(status string) string {
+	if status == "" {
+		return "Unknown"
+	}
+	return status
+}
+
+// CurrentStatus returns the status declared by an ADR: the front matter
+// status, or else the first line of the ## Status section
+func CurrentStatus(content string) string {
+	if fm, _, err := frontmatter.Parse(content); err == nil && fm != nil && fm.Status != "" {
+		return fm.Status
+	}
+	_, body, _, _ := frontmatter.Split(content)
+	status := extractStatusFromContent(body)
+	if status == "Unknown" {
+		return ""
+	}
+	return status
+}
+
+// SetStatus replaces the status in the front matter and in the first line
+// of the ## Status section, keeping any text after the status word. ok is
+// false when the ADR declares its status in neither place.
+func SetStatus(content, status string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	found := false
+
+	// Front matter status key
+	start := 0
+	if _, _, blockLines, ok := frontmatter.Split(content); ok {
+		for i := 1; i < blockLines-1; i++ {
+			if m := frontMatterStatusPattern.FindStringSubmatch(lines[i]); m != nil {
+				lines[i] = m[1] + m[2] + status + m[4] + m[5]
+				found = true
+				break
+			}
+		}
+		start = blockLines
+	}
+
+	// First line of the Status section
+	inStatus, inCode := false, false
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			inStatus = isStatusHeading(trimmed)
+			continue
+		}
+		if inStatus && trimmed != "" {
+			word := adrref.StatusWord(trimmed)
+			lines[i] = strings.Replace(lines[i], word, status, 1)
+			found = true
+			break
+		}
+	}
+
+	return strings.Join(lines, "\n"), found
+}
+
+// AppendStatusHistory adds entry as the last item of the Status History
+// section. A missing section is created after the Status section, or at
+// the end of the ADR (before a closing --- footer) when there is none.
+func AppendStatusHistory(content, entry string) string {
+	lines := strings.Split(content, "\n")
+
+	historyAt, statusAt := -1, -1
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || !strings.HasPrefix(trimmed, "## ") {
+			continue
+		}
+		heading := strings.TrimSpace(trimmed[3:])
+		if strings.EqualFold(heading, StatusHistoryHeading) && historyAt < 0 {
+			historyAt = i
+		} else if isStatusHeading(trimmed) && statusAt < 0 {
+			statusAt = i
+		}
+	}
+
+	if historyAt >= 0 {
+		// After the last non-blank line of the section
+		at := sectionEnd(lines, historyAt)
+		for at > historyAt+1 && strings.TrimSpace(lines[at-1]) == "" {
+			at--
+		}
+		return strings.Join(insertAt(lines, at, []string{entry}), "\n")
+	}
+
+	at := len(lines)
+	if statusAt >= 0 {
+		at = sectionEnd(lines, statusAt)
+	} else {
+		at = footerStart(lines)
+	}
+	if at > 0 && at == len(lines) && strings.TrimSpace(lines[at-1]) == "" {
+		at-- // Keep the trailing newline last
+	}
+	return strings.Join(insertBlock(lines, at, []string{"## " + StatusHistoryHeading, "", entry}), "\n")
+}
+
+// isStatusHeading reports whether a heading line is the Status section
+func isStatusHeading(trimmed string) bool {
+	return strings.EqualFold(strings.TrimSpace(strings.TrimLeft(trimmed, "#")), "Status")
+}
+
+// sectionEnd returns the index of the next H1 or H2 heading after the
+// heading at start, or the end of the document
+func sectionEnd(lines []string, start int) int {
+	inCode := false
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if !inCode && (strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "## ")) {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// footerStart returns the index of a closing "---" footer after the last
+// heading, or the end of the document
+func footerStart(lines []string) int {
+	for i := len(lines) - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		if trimmed == "---" {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// insertBlock inserts block at index at, separated from the surrounding
+// lines by single blank lines
+func insertBlock(lines []string, at int, block []string) []string {
+	if at > 0 && strings.TrimSpace(lines[at-1]) != "" {
+		block = append([]string{""}, block...)
+	}
+	if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+		block = append(block, "")
+	}
+	return insertAt(lines, at, block)
+}
+
+// insertAt returns lines with block inserted at index at
+func insertAt(lines []string, at int, block []string) []string {
+	out := make([]string, 0, len(lines)+len(block))
+	out = append(out, lines[:at]...)
+	out = append(out, block...)
+	return append(out, lines[at:]...)
+}
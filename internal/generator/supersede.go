@@ -0,0 +1,211 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/adrref"
+	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+)
+
+// Supersession describes an ADR replaced by a newly created one
+type Supersession struct {
+	Old       *StatusChange // Nil while the new ADR is still Proposed
+	NewNumber string
+	NewPath   string // Path of the new ADR relative to the project root
+}
+
+// Supersede creates the ADR configured on the creator as the replacement
+// of ADR oldNumber, with a "Supersedes" link back. The old ADR's category is
+// used unless one is configured.
+//
+// A Proposed replacement leaves the old ADR alone; it is superseded when
+// the new ADR is accepted with ChangeStatus, which also records the reason,
+// so a reason is rejected here. A replacement created in any
+// other status supersedes the old ADR at once: it moves to Superseded with
+// a "Superseded by" link and a status history entry. Nothing is written
+// unless both files can be prepared, and the new ADR is removed again if the
+// old one cannot be rewritten, so the log never ends up with only one side
+// of the link.
+func (c *ADRCreator) Supersede(oldNumber int, author, reason string, date time.Time) (*Supersession, error) {
+	project := c.config.Project
+
+	status, err := c.resolveStatus()
+	if err != nil {
+		return nil, err
+	}
+	c.config.Status = status
+
+	// Load the log without git history; only titles and categories are needed
+	lookup := *project
+	lookup.GitMetadata = false
+	g := New(&lookup)
+	if err := g.LoadADRsOnly(); err != nil {
+		return nil, err
+	}
+	old, err := loadedADR(g, project.ADRDirectory, oldNumber)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := supersededStatus(project, old); err != nil {
+		return nil, err
+	}
+	proposed := strings.EqualFold(c.config.Status, "Proposed")
+	if proposed && reason != "" {
+		return nil, fmt.Errorf("a reason is recorded when ADR-%s is superseded, which waits until the new ADR is accepted; "+
+			"give it then with \"adr-gen status <number> accepted --reason ...\", or create the new ADR as Accepted", old.Number)
+	}
+
+	if c.config.Category == "" {
+		c.config.Category = old.Category
+	}
+	planned, err := c.plan()
+	if err != nil {
+		return nil, err
+	}
+	newNumber := fmt.Sprintf("%04d", planned.Number)
+
+	// The new ADR links to the old one
+	toOld, err := relativeLink(planned.Dir, old.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	newContent := insertStatusNote(planned.Content,
+		fmt.Sprintf("Supersedes [ADR-%s: %s](%s)", old.Number, old.Title, toOld))
+
+	// Unless the new ADR is still a proposal, the old one becomes Superseded
+	// and links to it
+	var oldContent string
+	var change *StatusChange
+	if !proposed {
+		oldContent, change, err = supersedeContent(project, old, newNumber, c.config.Title, planned.Path, author, reason, date)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Write the new ADR first and roll it back if the old one fails
+	if err := os.MkdirAll(planned.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create ADR directory: %w", err)
+	}
+	if err := adrfs.WriteFileAtomic(planned.Path, []byte(newContent)); err != nil {
+		return nil, fmt.Errorf("failed to write ADR file: %w", err)
+	}
+	if change != nil {
+		if err := adrfs.WriteFileAtomic(old.FilePath, []byte(oldContent)); err != nil {
+			os.Remove(planned.Path)
+			return nil, fmt.Errorf("failed to write %s: %w", old.FilePath, err)
+		}
+	}
+
+	return &Supersession{Old: change, NewNumber: newNumber, NewPath: planned.relPath()}, nil
+}
+
+// supersededStatus returns the configured spelling of Superseded, after
+// checking that status_transitions lets old move there
+func supersededStatus(project *config.Config, old *ADR) (string, error) {
+	superseded, ok := canonicalStatus(project, "Superseded")
+	if !ok {
+		return "", fmt.Errorf("status Superseded is not one of the allowed statuses (%s)", strings.Join(project.AllowedStatuses, ", "))
+	}
+	from := adrref.StatusWord(old.Status)
+	if strings.EqualFold(from, superseded) {
+		return "", fmt.Errorf("ADR-%s is already Superseded", old.Number)
+	}
+	if !project.CanTransition(from, superseded) {
+		return "", fmt.Errorf("ADR-%s is %s, which cannot be superseded (allowed from %s: %s)",
+			old.Number, from, from, allowedNext(project, from))
+	}
+	return superseded, nil
+}
+
+// supersedeContent returns the old ADR moved to Superseded, with a
+// "Superseded by" link to the replacement at path and a status history
+// entry
+func supersedeContent(project *config.Config, old *ADR, number, title, path, author, reason string, date time.Time) (string, *StatusChange, error) {
+	superseded, err := supersededStatus(project, old)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := os.ReadFile(old.FilePath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read %s: %w", old.FilePath, err)
+	}
+	oldNumber, _ := strconv.Atoi(old.Number)
+	if reason == "" {
+		reason = "Replaced by ADR-" + number
+	}
+	content, change, err := applyStatus(project, string(data), StatusUpdate{
+		Number: oldNumber,
+		Status: superseded,
+		Author: author,
+		Reason: reason,
+		Date:   date,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	change.Path = old.FilePath
+
+	link, err := relativeLink(filepath.Dir(old.FilePath), path)
+	if err != nil {
+		return "", nil, err
+	}
+	content = insertStatusNote(content, fmt.Sprintf("Superseded by [ADR-%s: %s](%s)", number, title, link))
+	return content, change, nil
+}
+
+// relativeLink returns the markdown link target for path from an ADR in dir
+func relativeLink(dir, path string) (string, error) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to link %s from %s: %w", path, dir, err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// insertStatusNote adds note as a paragraph after the status line of the
+// ## Status section, or after the H1 title when the ADR has no such section
+func insertStatusNote(content, note string) string {
+	lines := strings.Split(content, "\n")
+
+	start := 0
+	if _, _, blockLines, ok := frontmatter.Split(content); ok {
+		start = blockLines
+	}
+
+	title := -1
+	inStatus, inCode := false, false
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "# ") && title < 0 {
+			title = i
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			inStatus = isStatusHeading(trimmed)
+			continue
+		}
+		if inStatus && trimmed != "" {
+			return strings.Join(insertBlock(lines, i+1, []string{note}), "\n")
+		}
+	}
+
+	if title >= 0 {
+		return strings.Join(insertBlock(lines, title+1, []string{note}), "\n")
+	}
+	return strings.Join(insertBlock(lines, start, []string{note}), "\n")
+}
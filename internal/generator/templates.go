@@ -61,6 +61,10 @@ func (g *Generator) loadTemplates() error {
 			}
 			return groups
 		},
+		"collapseChains": collapseChains,
+		"join": func(items []string, sep string) string {
+			return strings.Join(items, sep)
+		},
 		"contains": func(s, substr string) bool {
 			return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 		},
@@ -158,6 +162,35 @@ func (g *Generator) generateSearchPage() error {
 	return nil
 }
 
+// glossaryPageData holds the data of the glossary page
+type glossaryPageData struct {
+	Title          string
+	Entries        []GlossaryEntry
+	ADRs           []*ADR
+	BaseURL        string
+	BreadcrumbType string
+}
+
+// glossaryPageData returns the data rendered by glossary.html
+func (g *Generator) glossaryPageData() glossaryPageData {
+	return glossaryPageData{
+		Title:          "Glossary",
+		Entries:        g.Glossary(),
+		ADRs:           g.adrs,
+		BaseURL:        g.config.BaseURL,
+		BreadcrumbType: "glossary",
+	}
+}
+
+// generateGlossaryPage creates the glossary page from the configured terms
+func (g *Generator) generateGlossaryPage() error {
+	if err := g.renderPage("glossary.html", "glossary.html", g.glossaryPageData()); err != nil {
+		return err
+	}
+	g.stats.PageCount++
+	return nil
+}
+
 // renderPage renders a template to a file
 func (g *Generator) renderPage(templateName, filename string, data interface{}) error {
 	outputPath := filepath.Join(g.config.OutputDirectory, filename)
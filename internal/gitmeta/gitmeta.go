@@ -0,0 +1,212 @@
+package gitmeta
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotRepository is returned when the directory is not inside a git work tree
+var ErrNotRepository = errors.New("not a git repository")
+
+// fieldSep and recordSep delimit fields in the git log output
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// History holds the authorship information derived from a file's commits
+type History struct {
+	CreatedAt    time.Time // Date of the first commit touching the file
+	ModifiedAt   time.Time // Date of the most recent commit touching the file
+	Author       string    // Author of the first commit
+	Contributors []string  // Every commit author, in order of first contribution
+	CommitCount  int
+}
+
+// Repository reads file history from a local git repository. It only runs
+// local git commands, so it works fully offline.
+type Repository struct {
+	dir     string
+	shallow bool
+	cache   map[string]*History
+	mutex   sync.Mutex
+}
+
+// Open returns a Repository for the work tree containing dir
+func Open(dir string) (*Repository, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git executable not found: %w", err)
+	}
+
+	repo := &Repository{
+		dir:   dir,
+		cache: make(map[string]*History),
+	}
+
+	out, err := repo.git("rev-parse", "--is-inside-work-tree")
+	if err != nil || strings.TrimSpace(out) != "true" {
+		return nil, ErrNotRepository
+	}
+
+	if out, err := repo.git("rev-parse", "--is-shallow-repository"); err == nil {
+		repo.shallow = strings.TrimSpace(out) == "true"
+	}
+
+	return repo, nil
+}
+
+// IsShallow reports whether the clone is shallow, in which case creation
+// dates only go back as far as the fetched history
+func (r *Repository) IsShallow() bool {
+	return r.shallow
+}
+
+// FileHistory returns the history of path, following renames. It returns
+// nil without an error when the file has no commits yet.
+func (r *Repository) FileHistory(path string) (*History, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if history, ok := r.cache[path]; ok {
+		return history, nil
+	}
+
+	format := "--format=" + recordSep + "%an" + fieldSep + "%aI"
+	out, err := r.git("log", "--follow", format, "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history for %s: %w", path, err)
+	}
+
+	history := parseLog(out)
+	r.cache[path] = history
+	return history, nil
+}
+
+// parseLog builds a History from newest-first git log records
+func parseLog(out string) *History {
+	records := strings.Split(out, recordSep)
+
+	var history *History
+	seen := make(map[string]bool)
+
+	// Walk oldest first so contributors are listed in order of first commit
+	for i := len(records) - 1; i >= 0; i-- {
+		fields := strings.Split(strings.TrimSpace(records[i]), fieldSep)
+		if len(fields) != 2 {
+			continue
+		}
+
+		author := strings.TrimSpace(fields[0])
+		date, err := time.Parse(time.RFC3339, strings.TrimSpace(fields[1]))
+		if err != nil {
+			continue
+		}
+
+		if history == nil {
+			history = &History{CreatedAt: date, Author: author}
+		}
+		history.ModifiedAt = date
+		history.CommitCount++
+
+		if author != "" && !seen[author] {
+			seen[author] = true
+			history.Contributors = append(history.Contributors, author)
+		}
+	}
+
+	return history
+}
+
+// ChangedFiles lists the files that changed since ref, relative to the
+// repository directory. Changes are taken from the merge base of ref and
+// HEAD, so commits that landed on ref after the branch point are ignored,
+// and include uncommitted and untracked files. Deleted files are skipped.
+func (r *Repository) ChangedFiles(ref string) ([]string, error) {
+	changed, err := r.git("diff", "--name-only", "--relative", "--diff-filter=d", r.MergeBase(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes since %s: %w", ref, err)
+	}
+	untracked, err := r.git("ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(changed+"\n"+untracked, "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		files = append(files, filepath.Join(r.dir, filepath.FromSlash(name)))
+	}
+	return files, nil
+}
+
+// MergeBase returns the commit where HEAD branched off ref, or ref itself
+// when there is no common ancestor
+func (r *Repository) MergeBase(ref string) string {
+	if out, err := r.git("merge-base", ref, "HEAD"); err == nil {
+		return strings.TrimSpace(out)
+	}
+	return ref
+}
+
+// FileAt returns the content of path at ref. ok is false when the file did
+// not exist at ref; an error means ref itself could not be resolved.
+func (r *Repository) FileAt(ref, path string) (content string, ok bool, err error) {
+	if !r.HasRevision(ref) {
+		return "", false, fmt.Errorf("unknown git revision %s", ref)
+	}
+
+	dir, err := filepath.Abs(r.dir)
+	if err != nil {
+		return "", false, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false, err
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil {
+		return "", false, err
+	}
+	out, err := r.git("show", ref+":./"+filepath.ToSlash(rel))
+	if err != nil {
+		return "", false, nil
+	}
+	return out, true, nil
+}
+
+// HasRevision reports whether ref names a commit, which is false for HEAD
+// in a repository without commits
+func (r *Repository) HasRevision(ref string) bool {
+	_, err := r.git("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return err == nil
+}
+
+// git runs a git command in the repository directory
+func (r *Repository) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// UserName returns the git user.name configured for dir, or "" when git or
+// the setting is unavailable
+func UserName(dir string) string {
+	cmd := exec.Command("git", "config", "user.name")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,41 @@
+package markdown
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// headingParser parses with the same extensions and heading ID generation
+// as NewSimple, so IDs match the anchors on rendered pages
+var headingParser = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		extension.Footnote,
+		extension.DefinitionList,
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+).Parser()
+
+// HeadingIDs returns the set of anchor IDs goldmark generates for the
+// headings in content
+func HeadingIDs(content string) map[string]bool {
+	source := []byte(content)
+	doc := headingParser.Parse(text.NewReader(source))
+
+	ids := make(map[string]bool)
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || node.Kind() != ast.KindHeading {
+			return ast.WalkContinue, nil
+		}
+		if id, ok := node.AttributeString("id"); ok {
+			if value, ok := id.([]byte); ok {
+				ids[string(value)] = true
+			}
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return ids
+}
@@ -146,17 +146,19 @@ func (p *SimpleProcessor) processMermaidDiagrams(html string) string {
 func (p *SimpleProcessor) processADRLinks(html string) string {
 	// Match links to ADR markdown files
 	// Pattern: <a href="NNNN-some-title.md">Link Text</a>
-	re := regexp.MustCompile(`<a href="([0-9]{4}-[a-z0-9-]+\.md)"([^>]*)>([^<]*)</a>`)
+	// Relative folder prefixes and #anchors are kept working on the flat site
+	re := regexp.MustCompile(`<a href="(?:(?:\.{1,2}/|[a-z0-9-]+/)*)([0-9]{4}-[a-z0-9-]+\.md)(#[^"]*)?"([^>]*)>([^<]*)</a>`)
 	
 	html = re.ReplaceAllStringFunc(html, func(match string) string {
 		matches := re.FindStringSubmatch(match)
-		if len(matches) != 4 {
+		if len(matches) != 5 {
 			return match
 		}
 		
 		markdownFile := matches[1]   // e.g., "0001-record-architecture-decisions.md"
-		attributes := matches[2]     // any additional attributes
-		linkText := matches[3]       // the link text
+		anchor := matches[2]         // e.g., "#context", or empty
+		attributes := matches[3]     // any additional attributes
+		linkText := matches[4]       // the link text
 		
 		// Extract ADR number from filename
 		adrNumber := extractADRNumberFromFilename(markdownFile)
@@ -170,7 +172,7 @@ func (p *SimpleProcessor) processADRLinks(html string) string {
 			htmlURL = fmt.Sprintf("/adr-%s.html", adrNumber)
 		}
 		
-		return fmt.Sprintf(`<a href="%s"%s>%s</a>`, htmlURL, attributes, linkText)
+		return fmt.Sprintf(`<a href="%s%s"%s>%s</a>`, htmlURL, anchor, attributes, linkText)
 	})
 	
 	return html
@@ -0,0 +1,408 @@
+package mermaid
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// flowchartDirections are the valid flowchart orientations
+	flowchartDirections = map[string]bool{"TB": true, "TD": true, "BT": true, "RL": true, "LR": true}
+
+	// flowchartArrowPattern matches valid flowchart link operators, including
+	// the halves of links with inline text such as "A -- text --> B"
+	flowchartArrowPattern = regexp.MustCompile(`^<?(?:-{2,}|={2,}|-\.+-|-\.+|\.+-)>?$`)
+
+	// arrowRunPattern finds candidate link operators in a masked statement
+	arrowRunPattern = regexp.MustCompile(`[-=.<>]{2,}`)
+
+	// asymmetricShapePattern matches the A>text] node shape
+	asymmetricShapePattern = regexp.MustCompile(`([A-Za-z0-9_])>([^\]\-=>]*)\]`)
+
+	// identifierPattern matches node identifiers
+	identifierPattern = regexp.MustCompile(`[A-Za-z0-9_]+`)
+
+	// messagePattern matches sequence messages such as "A->>B: text"
+	messagePattern = regexp.MustCompile(`^([^\s:]+?)\s*(<<-->>|<<->>|-->>|->>|--x|-x|--\)|-\)|-->|->)([+-]?)\s*([^\s:]+)\s*:(.*)$`)
+
+	// notePattern matches sequence notes such as "Note over A,B: text"
+	notePattern = regexp.MustCompile(`(?i)^note\s+(?:over|left of|right of)\s+([^:]+):`)
+
+	// stateNotePattern matches state notes such as "note right of Hot"
+	stateNotePattern = regexp.MustCompile(`^note\s+(?:left|right)\s+of\s+(\S+)`)
+
+	// c4CallPattern matches C4 statements such as Rel(a, b, "label") {
+	c4CallPattern = regexp.MustCompile(`^(\w+)\s*\((.*)\)\s*(\{)?$`)
+)
+
+// sequenceBlocks are the sequence diagram statements closed by "end"
+var sequenceBlocks = map[string]bool{
+	"loop": true, "alt": true, "opt": true, "par": true, "critical": true,
+	"break": true, "rect": true, "box": true,
+}
+
+// checkFlowchart validates flowchart and graph diagrams
+func checkFlowchart(d *diagram) {
+	header := strings.Fields(d.lines[d.header])
+	if len(header) > 1 && !flowchartDirections[header[1]] {
+		d.errorf(d.header, columnOf(d.lines[d.header], header[1]),
+			"Invalid flowchart direction %q (use TB, TD, BT, RL or LR)", header[1])
+	}
+
+	nested := &blocks{d: d}
+	references := &refs{d: d}
+	defined := make(map[string]bool)
+
+	d.statements(func(index int, line, trimmed string) {
+		fields := strings.Fields(trimmed)
+		switch fields[0] {
+		case "subgraph":
+			nested.open("subgraph", index)
+			if len(fields) > 1 {
+				defined[identifierPattern.FindString(fields[1])] = true
+			}
+			d.checkBalance(index, line, len(line), false)
+			return
+		case "end":
+			nested.close(index, line, "end")
+			return
+		case "direction", "classDef", "linkStyle":
+			return
+		case "style", "click":
+			if len(fields) > 1 {
+				references.add(fields[0], fields[1], index, line)
+			}
+			return
+		case "class":
+			if len(fields) > 1 {
+				for _, name := range strings.Split(fields[1], ",") {
+					references.add("class", name, index, line)
+				}
+			}
+			return
+		}
+
+		// Treat the asymmetric A>text] shape like A[text]
+		statement := asymmetricShapePattern.ReplaceAllString(line, "$1[$2]")
+		if !d.checkBalance(index, statement, len(statement), true) {
+			return
+		}
+
+		masked := maskLabels(statement)
+		for _, loc := range arrowRunPattern.FindAllStringIndex(masked, -1) {
+			arrow := masked[loc[0]:loc[1]]
+			if !flowchartArrowPattern.MatchString(arrow) {
+				d.errorf(index, loc[0]+1, "Invalid arrow %q (use -->, ---, -.->, ==> or <-->)", arrow)
+			}
+		}
+		for _, name := range identifierPattern.FindAllString(masked, -1) {
+			defined[name] = true
+		}
+	})
+
+	nested.finish("end")
+	references.check(defined, "node", false)
+}
+
+// maskLabels blanks out node labels, quoted strings and edge labels so only
+// node identifiers and link operators remain
+func maskLabels(line string) string {
+	masked := []byte(line)
+	depth := 0
+	inQuote, inPipe := false, false
+	for i := 0; i < len(masked); i++ {
+		c := masked[i]
+		switch {
+		case inQuote:
+			inQuote = c != '"'
+		case inPipe:
+			inPipe = c != '|'
+		case c == '"':
+			inQuote = true
+		case c == '|':
+			inPipe = true
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case depth == 0:
+			continue
+		}
+		masked[i] = ' '
+	}
+	return string(masked)
+}
+
+// checkSequence validates sequence diagrams
+func checkSequence(d *diagram) {
+	nested := &blocks{d: d}
+	references := &refs{d: d}
+	declared := make(map[string]bool)
+
+	d.statements(func(index int, line, trimmed string) {
+		fields := strings.Fields(trimmed)
+		keyword := fields[0]
+		if keyword == "create" && len(fields) > 1 {
+			keyword, fields = fields[1], fields[1:]
+		}
+
+		switch {
+		case keyword == "participant" || keyword == "actor":
+			if len(fields) < 2 {
+				d.errorf(index, indent(line)+1, "%s needs a name", keyword)
+				return
+			}
+			declared[fields[1]] = true
+			d.checkBalance(index, line, len(line), false)
+			return
+		case sequenceBlocks[keyword]:
+			nested.open(keyword, index)
+			return
+		case keyword == "else":
+			if !nested.inside("alt", "critical") {
+				d.errorf(index, indent(line)+1, "else outside of an alt block")
+			}
+			return
+		case keyword == "and":
+			if !nested.inside("par") {
+				d.errorf(index, indent(line)+1, "and outside of a par block")
+			}
+			return
+		case keyword == "option":
+			if !nested.inside("critical") {
+				d.errorf(index, indent(line)+1, "option outside of a critical block")
+			}
+			return
+		case keyword == "end":
+			nested.close(index, line, "end")
+			return
+		case keyword == "activate" || keyword == "deactivate" || keyword == "destroy":
+			if len(fields) > 1 {
+				references.add(keyword, fields[1], index, line)
+			}
+			return
+		case keyword == "autonumber" || keyword == "title" || keyword == "link" || keyword == "links":
+			return
+		case strings.EqualFold(keyword, "note"):
+			match := notePattern.FindStringSubmatch(trimmed)
+			if match == nil {
+				d.errorf(index, indent(line)+1, "Invalid note, expected \"Note over A: text\"")
+				return
+			}
+			for _, name := range strings.Split(match[1], ",") {
+				references.add("Note", strings.TrimSpace(name), index, line)
+			}
+			return
+		}
+
+		match := messagePattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			d.errorf(index, indent(line)+1, "Invalid message, expected \"A->>B: text\"")
+			return
+		}
+		d.checkBalance(index, line, strings.Index(line, ":"), false)
+		references.add("Message", match[1], index, line)
+		references.add("Message", match[4], index, line)
+	})
+
+	nested.finish("end")
+
+	// Participants are created implicitly, so only flag names that were
+	// never declared when the diagram declares its participants
+	if len(declared) > 0 {
+		references.check(declared, "participant", true)
+	}
+}
+
+// checkState validates state diagrams
+func checkState(d *diagram) {
+	nested := &blocks{d: d}
+	references := &refs{d: d}
+	defined := map[string]bool{"[*]": true}
+	inNote := false
+
+	d.statements(func(index int, line, trimmed string) {
+		if inNote {
+			inNote = trimmed != "end note"
+			return
+		}
+
+		fields := strings.Fields(trimmed)
+		switch {
+		case trimmed == "}":
+			nested.close(index, line, "}")
+			return
+		case trimmed == "--" || fields[0] == "direction" || fields[0] == "classDef":
+			return
+		case fields[0] == "class" && len(fields) > 1:
+			for _, name := range strings.Split(fields[1], ",") {
+				references.add("class", name, index, line)
+			}
+			return
+		case fields[0] == "note":
+			match := stateNotePattern.FindStringSubmatch(trimmed)
+			if match == nil {
+				d.errorf(index, indent(line)+1, "Invalid note, expected \"note right of State\"")
+				return
+			}
+			references.add("note", strings.TrimSuffix(match[1], ":"), index, line)
+			inNote = !strings.Contains(trimmed, ":")
+			return
+		case fields[0] == "state":
+			if !d.checkBalance(index, line, len(strings.TrimSuffix(strings.TrimRight(line, " \t"), "{")), false) {
+				return
+			}
+			name := fields[len(fields)-1]
+			if name == "{" {
+				nested.open("state", index)
+				name = fields[len(fields)-2]
+			}
+			if strings.HasPrefix(name, "<<") && len(fields) > 2 {
+				name = fields[1]
+			}
+			defined[name] = true
+			return
+		}
+
+		// Transitions and descriptions: everything after ':' is free text
+		limit := len(line)
+		if colon := strings.Index(line, ":"); colon >= 0 {
+			limit = colon
+		}
+		if !d.checkBalance(index, line, limit, false) {
+			return
+		}
+		statement := line[:limit]
+
+		if !strings.Contains(statement, "-->") {
+			if loc := arrowRunPattern.FindStringIndex(statement); loc != nil {
+				d.errorf(index, loc[0]+1, "Invalid transition %q (use -->)", statement[loc[0]:loc[1]])
+				return
+			}
+			for _, name := range strings.Fields(statement) {
+				defined[name] = true
+			}
+			return
+		}
+
+		for i, side := range strings.Split(statement, "-->") {
+			name := strings.TrimSpace(side)
+			if name == "" || strings.ContainsAny(name, " \t") {
+				d.errorf(index, indent(line)+1, "Transition needs a state on both sides of -->")
+				return
+			}
+			defined[name] = true
+			if i > 1 {
+				d.errorf(index, indent(line)+1, "Only one --> is allowed per transition")
+				return
+			}
+		}
+	})
+
+	if inNote {
+		d.errorf(len(d.lines)-1, 1, "Unclosed note (missing \"end note\")")
+	}
+	nested.finish("}")
+	references.check(defined, "state", false)
+}
+
+// c4Statement describes which arguments of a C4 statement define or
+// reference elements
+type c4Statement struct {
+	defines  bool  // The first argument is the alias of a new element
+	refs     []int // Arguments that must name existing elements
+	minArgs  int
+	canBlock bool // May open a { } block
+}
+
+// c4Statements lists the supported C4 statements
+var c4Statements = func() map[string]c4Statement {
+	statements := make(map[string]c4Statement)
+	element := c4Statement{defines: true, minArgs: 2}
+	for _, name := range []string{
+		"Person", "Person_Ext",
+		"System", "System_Ext", "SystemDb", "SystemDb_Ext", "SystemQueue", "SystemQueue_Ext",
+		"Container", "Container_Ext", "ContainerDb", "ContainerDb_Ext", "ContainerQueue", "ContainerQueue_Ext",
+		"Component", "Component_Ext", "ComponentDb", "ComponentDb_Ext", "ComponentQueue", "ComponentQueue_Ext",
+	} {
+		statements[name] = element
+	}
+	boundary := c4Statement{defines: true, minArgs: 2, canBlock: true}
+	for _, name := range []string{
+		"Boundary", "Enterprise_Boundary", "System_Boundary", "Container_Boundary",
+		"Deployment_Node", "Node", "Node_L", "Node_R",
+	} {
+		statements[name] = boundary
+	}
+	relation := c4Statement{refs: []int{0, 1}, minArgs: 3}
+	for _, name := range []string{
+		"Rel", "BiRel", "Rel_U", "Rel_Up", "Rel_D", "Rel_Down", "Rel_L", "Rel_Left",
+		"Rel_R", "Rel_Right", "Rel_Back",
+	} {
+		statements[name] = relation
+	}
+	statements["RelIndex"] = c4Statement{refs: []int{1, 2}, minArgs: 4}
+	statements["UpdateElementStyle"] = c4Statement{refs: []int{0}, minArgs: 1}
+	statements["UpdateBoundaryStyle"] = c4Statement{refs: []int{0}, minArgs: 1}
+	statements["UpdateRelStyle"] = c4Statement{refs: []int{0, 1}, minArgs: 2}
+	for _, name := range []string{"UpdateLayoutConfig", "AddElementTag", "AddRelTag"} {
+		statements[name] = c4Statement{}
+	}
+	return statements
+}()
+
+// checkC4 validates C4Context, C4Container, C4Component, C4Dynamic and
+// C4Deployment diagrams
+func checkC4(d *diagram) {
+	nested := &blocks{d: d}
+	references := &refs{d: d}
+	defined := make(map[string]bool)
+
+	d.statements(func(index int, line, trimmed string) {
+		if trimmed == "}" {
+			nested.close(index, line, "}")
+			return
+		}
+		if strings.HasPrefix(trimmed, "title ") || trimmed == "title" {
+			return
+		}
+
+		limit := len(strings.TrimSuffix(strings.TrimRight(line, " \t"), "{"))
+		if !d.checkBalance(index, line, limit, false) {
+			return
+		}
+
+		match := c4CallPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			d.errorf(index, indent(line)+1, "Invalid C4 statement, expected Element(alias, \"label\", ...)")
+			return
+		}
+
+		name, args := match[1], splitArgs(match[2])
+		statement, ok := c4Statements[name]
+		if !ok {
+			d.errorf(index, indent(line)+1, "Unknown C4 statement %q", name)
+			return
+		}
+		if len(args) < statement.minArgs {
+			d.errorf(index, indent(line)+1, "%s needs at least %d arguments, got %d", name, statement.minArgs, len(args))
+			return
+		}
+		if match[3] == "{" {
+			if !statement.canBlock {
+				d.errorf(index, len(strings.TrimRight(line, " \t")), "%s cannot contain other elements", name)
+			}
+			nested.open(name, index)
+		}
+
+		if statement.defines {
+			defined[args[0]] = true
+		}
+		for _, i := range statement.refs {
+			references.add(name, args[i], index, line)
+		}
+	})
+
+	nested.finish("}")
+	references.check(defined, "element", false)
+}
@@ -0,0 +1,321 @@
+package mermaid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Problem is a syntax problem found in a diagram. Line is 1-based and
+// counted from the first line of the diagram source.
+type Problem struct {
+	Line    int
+	Column  int
+	Message string
+	Warning bool // Mermaid renders the diagram, but probably not as intended
+}
+
+// checkers validate the body of each supported diagram kind
+var checkers = map[string]func(d *diagram){
+	"flowchart":       checkFlowchart,
+	"graph":           checkFlowchart,
+	"sequenceDiagram": checkSequence,
+	"stateDiagram":    checkState,
+	"stateDiagram-v2": checkState,
+	"C4Context":       checkC4,
+	"C4Container":     checkC4,
+	"C4Component":     checkC4,
+	"C4Dynamic":       checkC4,
+	"C4Deployment":    checkC4,
+}
+
+// otherKinds are valid Mermaid diagram types whose bodies are not checked
+var otherKinds = []string{
+	"classDiagram", "classDiagram-v2", "erDiagram", "gantt", "pie", "journey",
+	"gitGraph", "mindmap", "timeline", "quadrantChart", "requirementDiagram",
+	"sankey-beta", "xychart-beta", "block-beta",
+}
+
+// closers maps closing brackets to their opening bracket
+var closers = map[rune]rune{')': '(', ']': '[', '}': '{'}
+
+// diagram holds the state of a single check
+type diagram struct {
+	lines    []string
+	header   int // Index of the header line
+	problems []Problem
+}
+
+// Check validates a Mermaid diagram: the header keyword, bracket and quote
+// balance, arrow syntax and references to undefined nodes. Flowcharts,
+// sequence, state and C4 diagrams are checked in depth; other known
+// diagram types only have their header checked.
+func Check(source string) []Problem {
+	d := &diagram{lines: strings.Split(source, "\n"), header: -1}
+
+	// Find the header, skipping blank lines, comments and a config block
+	inConfig := false
+	for i, line := range d.lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "---":
+			inConfig = !inConfig
+			continue
+		case inConfig || trimmed == "" || strings.HasPrefix(trimmed, "%%"):
+			continue
+		}
+		d.header = i
+		break
+	}
+	if d.header < 0 {
+		d.errorf(0, 1, "Empty Mermaid diagram")
+		return d.problems
+	}
+
+	keyword := strings.Fields(d.lines[d.header])[0]
+	if check, ok := checkers[keyword]; ok {
+		check(d)
+		sort.SliceStable(d.problems, func(i, j int) bool {
+			return d.problems[i].Line < d.problems[j].Line
+		})
+		return d.problems
+	}
+	for _, kind := range otherKinds {
+		if keyword == kind {
+			return d.problems
+		}
+	}
+
+	message := fmt.Sprintf("Unknown diagram type %q", keyword)
+	if suggestion := suggestKind(keyword); suggestion != "" {
+		message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	d.errorf(d.header, indent(d.lines[d.header])+1, "%s", message)
+	return d.problems
+}
+
+// suggestKind finds a known diagram type differing only in case
+func suggestKind(keyword string) string {
+	for kind := range checkers {
+		if strings.EqualFold(kind, keyword) {
+			return kind
+		}
+	}
+	for _, kind := range otherKinds {
+		if strings.EqualFold(kind, keyword) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// errorf records an error at a line index and 1-based column
+func (d *diagram) errorf(index, column int, format string, args ...interface{}) {
+	d.problems = append(d.problems, Problem{
+		Line:    index + 1,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// warnf records a warning at a line index and 1-based column
+func (d *diagram) warnf(index, column int, format string, args ...interface{}) {
+	d.errorf(index, column, format, args...)
+	d.problems[len(d.problems)-1].Warning = true
+}
+
+// statements calls fn for every body line that is not blank or a comment
+func (d *diagram) statements(fn func(index int, line, trimmed string)) {
+	for i := d.header + 1; i < len(d.lines); i++ {
+		trimmed := strings.TrimSpace(d.lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "%%") {
+			continue
+		}
+		fn(i, d.lines[i], trimmed)
+	}
+}
+
+// checkBalance reports the first unbalanced bracket or quote in
+// line[:limit]. Text between pipes is skipped when pipes is set, as used by
+// flowchart edge labels. It returns false when a problem was reported.
+func (d *diagram) checkBalance(index int, line string, limit int, pipes bool) bool {
+	type open struct {
+		r      rune
+		column int
+	}
+	var stack []open
+	quote, pipe := 0, 0 // Columns of an open quote or pipe label
+
+	column := 0
+	for _, r := range line[:limit] {
+		column++
+		switch {
+		case quote > 0:
+			if r == '"' {
+				quote = 0
+			}
+		case pipe > 0:
+			if r == '|' {
+				pipe = 0
+			}
+		case r == '"':
+			quote = column
+		case r == '|' && pipes:
+			pipe = column
+		case r == '(' || r == '[' || r == '{':
+			stack = append(stack, open{r, column})
+		case closers[r] != 0:
+			if len(stack) == 0 || stack[len(stack)-1].r != closers[r] {
+				d.errorf(index, column, "Unexpected %q", r)
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	switch {
+	case quote > 0:
+		d.errorf(index, quote, "Unterminated string")
+	case pipe > 0:
+		d.errorf(index, pipe, "Unterminated edge label")
+	case len(stack) > 0:
+		top := stack[len(stack)-1]
+		d.errorf(index, top.column, "Unclosed %q", top.r)
+	default:
+		return true
+	}
+	return false
+}
+
+// block is an open subgraph, boundary, composite state or sequence block
+type block struct {
+	keyword string
+	index   int
+}
+
+// blocks tracks nested blocks and reports mismatched closers
+type blocks struct {
+	d     *diagram
+	stack []block
+}
+
+// open pushes a block started at line index
+func (b *blocks) open(keyword string, index int) {
+	b.stack = append(b.stack, block{keyword, index})
+}
+
+// close pops the innermost block, reporting a closer with nothing open
+func (b *blocks) close(index int, line, closer string) {
+	if len(b.stack) == 0 {
+		b.d.errorf(index, indent(line)+1, "Unexpected %q with no open block", closer)
+		return
+	}
+	b.stack = b.stack[:len(b.stack)-1]
+}
+
+// inside reports whether the innermost open block is one of keywords
+func (b *blocks) inside(keywords ...string) bool {
+	if len(b.stack) == 0 {
+		return false
+	}
+	top := b.stack[len(b.stack)-1].keyword
+	for _, keyword := range keywords {
+		if top == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// finish reports blocks left open at the end of the diagram
+func (b *blocks) finish(closer string) {
+	for _, open := range b.stack {
+		line := b.d.lines[open.index]
+		b.d.errorf(open.index, indent(line)+1, "Unclosed %s (missing %q)", open.keyword, closer)
+	}
+}
+
+// refs collects references to nodes so they can be checked once every
+// definition in the diagram has been seen
+type refs struct {
+	d     *diagram
+	items []ref
+}
+
+// ref is a single reference to a node
+type ref struct {
+	name   string
+	index  int
+	column int
+	kind   string // What the referencing statement is, for messages
+}
+
+// add records a reference to name found in line
+func (r *refs) add(kind, name string, index int, line string) {
+	r.items = append(r.items, ref{name: name, index: index, column: columnOf(line, name), kind: kind})
+}
+
+// check reports every reference missing from defined
+func (r *refs) check(defined map[string]bool, noun string, warn bool) {
+	for _, item := range r.items {
+		if defined[item.name] {
+			continue
+		}
+		if warn {
+			r.d.warnf(item.index, item.column, "%s references undeclared %s %q", item.kind, noun, item.name)
+		} else {
+			r.d.errorf(item.index, item.column, "%s references undefined %s %q", item.kind, noun, item.name)
+		}
+	}
+}
+
+// indent returns the number of leading whitespace characters in line
+func indent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// columnOf returns the 1-based column of the first whole-word occurrence of
+// name in line, or the first non-blank column
+func columnOf(line, name string) int {
+	for offset := 0; offset < len(line); {
+		i := strings.Index(line[offset:], name)
+		if i < 0 {
+			break
+		}
+		start, end := offset+i, offset+i+len(name)
+		if (start == 0 || !isWordByte(line[start-1])) && (end == len(line) || !isWordByte(line[end])) {
+			return utf8.RuneCountInString(line[:start]) + 1
+		}
+		offset = end
+	}
+	return indent(line) + 1
+}
+
+// isWordByte reports whether c can be part of a node identifier
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// splitArgs splits a comma-separated argument list, respecting quotes
+func splitArgs(s string) []string {
+	var args []string
+	var current strings.Builder
+	inQuote := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			current.WriteRune(r)
+		case r == ',' && !inQuote:
+			args = append(args, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if last := strings.TrimSpace(current.String()); last != "" || len(args) > 0 {
+		args = append(args, last)
+	}
+	return args
+}
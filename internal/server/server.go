@@ -16,6 +16,9 @@ type Config struct {
 	Host    string
 	Port    int
 	Verbose bool
+
+	// Project is the loaded ADR configuration (defaults are used when nil)
+	Project *config.Config
 }
 
 // Server represents the development server
@@ -34,7 +37,10 @@ func New(config *Config) *Server {
 // Start starts the development server
 func (s *Server) Start() error {
 	// Create generator for initial build and dynamic serving
-	genConfig := config.DefaultConfig()
+	genConfig := s.config.Project
+	if genConfig == nil {
+		genConfig = config.DefaultConfig()
+	}
 	genConfig.Verbose = s.config.Verbose
 
 	s.generator = generator.New(genConfig)
@@ -72,6 +78,8 @@ func (s *Server) setupRoutes() {
 	http.HandleFunc("/search.html", s.handleSearch)
 	http.HandleFunc("/search-index.json", s.handleSearchIndex)
 	http.HandleFunc("/docs", s.handleDocs)
+	http.HandleFunc("/glossary", s.handleGlossary)
+	http.HandleFunc("/glossary.html", s.handleGlossary)
 
 	// Serve static assets if they exist
 	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
@@ -117,6 +125,12 @@ func (s *Server) handleADR(w http.ResponseWriter, r *http.Request) {
 	adrPart := strings.TrimPrefix(path, "/adr-")
 	adrPart = strings.TrimSuffix(adrPart, ".html")
 
+	// Old numbers of renumbered ADRs move permanently
+	if target, ok := s.generator.RedirectTarget(adrPart); ok {
+		http.Redirect(w, r, fmt.Sprintf("/adr-%s.html", target.Number), http.StatusMovedPermanently)
+		return
+	}
+
 	// Render specific ADR page (will use cache if unchanged)
 	if err := s.generator.RenderADRPage(w, adrPart); err != nil {
 		http.Error(w, fmt.Sprintf("Failed to render ADR: %v", err), http.StatusNotFound)
@@ -137,6 +151,18 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleGlossary serves the glossary page built from the configuration
+func (s *Server) handleGlossary(w http.ResponseWriter, r *http.Request) {
+	if err := s.generator.RenderGlossaryPage(w); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("Failed to render glossary: %v", err),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+}
+
 // handleDocs serves the documentation page with README.md content
 func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
 	// Read README.md file
@@ -176,12 +202,19 @@ func (s *Server) handleSearchIndex(w http.ResponseWriter, r *http.Request) {
 // generateSearchIndex creates search index from current ADRs
 func (s *Server) generateSearchIndex() map[string]interface{} {
 	type SearchItem struct {
-		Number      string `json:"number"`
-		Title       string `json:"title"`
-		Status      string `json:"status"`
-		Content     string `json:"content"`
-		DiagramType string `json:"diagramType"`
-		URL         string `json:"url"`
+		Number       string   `json:"number"`
+		Title        string   `json:"title"`
+		Status       string   `json:"status"`
+		Content      string   `json:"content"`
+		DiagramType  string   `json:"diagramType"`
+		URL          string   `json:"url"`
+		Category     string   `json:"category,omitempty"`
+		Date         string   `json:"date,omitempty"`
+		Author       string   `json:"author,omitempty"`
+		Deciders     []string `json:"deciders,omitempty"`
+		Tags         []string `json:"tags,omitempty"`
+		Supersedes   []string `json:"supersedes,omitempty"`
+		SupersededBy []string `json:"supersededBy,omitempty"`
 	}
 
 	var searchItems []SearchItem
@@ -199,12 +232,19 @@ func (s *Server) generateSearchIndex() map[string]interface{} {
 		}
 
 		item := SearchItem{
-			Number:      adr.Number,
-			Title:       adr.Title,
-			Status:      adr.Status,
-			Content:     cleanContent,
-			DiagramType: adr.DiagramType,
-			URL:         fmt.Sprintf("adr-%s.html", adr.Number),
+			Number:       adr.Number,
+			Title:        adr.Title,
+			Status:       adr.Status,
+			Content:      cleanContent,
+			DiagramType:  adr.DiagramType,
+			URL:          fmt.Sprintf("adr-%s.html", adr.Number),
+			Category:     adr.Category,
+			Date:         adr.Date,
+			Author:       adr.Author,
+			Deciders:     adr.Deciders,
+			Tags:         adr.Tags,
+			Supersedes:   adr.Supersedes,
+			SupersededBy: adr.SupersededBy,
 		}
 
 		searchItems = append(searchItems, item)
@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+)
+
+// DefaultBaselineFile is where validate --update-baseline records issues
+const DefaultBaselineFile = ".adr-baseline.json"
+
+// baselineVersion is the format version written to baseline files
+const baselineVersion = 1
+
+// Baseline records known issues so that only new ones fail validation.
+// Issues are matched by rule, file and message but not by line, so edits
+// elsewhere in a file do not resurrect them. Count allows an issue to occur
+// that many times in the file.
+type Baseline struct {
+	Version int             `json:"version"`
+	Issues  []BaselineEntry `json:"issues"`
+}
+
+// BaselineEntry is a known issue and how often it occurs
+type BaselineEntry struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// baselineKey identifies an issue in a baseline
+type baselineKey struct {
+	rule, file, message string
+}
+
+// NewBaseline records every issue in result
+func NewBaseline(result *ValidationResult) *Baseline {
+	counts := make(map[baselineKey]int)
+	for _, issue := range result.Issues {
+		counts[baselineKey{issue.Rule, issue.File, issue.Message}]++
+	}
+
+	baseline := &Baseline{Version: baselineVersion, Issues: []BaselineEntry{}}
+	for key, count := range counts {
+		baseline.Issues = append(baseline.Issues, BaselineEntry{
+			Rule:    key.rule,
+			File:    key.file,
+			Message: key.message,
+			Count:   count,
+		})
+	}
+	// Sorted so that the file diffs cleanly in review
+	sort.Slice(baseline.Issues, func(i, j int) bool {
+		a, b := baseline.Issues[i], baseline.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+	return baseline
+}
+
+// LoadBaseline reads a baseline file. A missing file is reported with an
+// error that satisfies errors.Is(err, os.ErrNotExist).
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if baseline.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s; run adr-gen validate --update-baseline", baseline.Version, path)
+	}
+	return &baseline, nil
+}
+
+// Save writes the baseline atomically as indented JSON
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := adrfs.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write baseline %s: %w", path, err)
+	}
+	return nil
+}
+
+// Apply removes the issues recorded in the baseline from result and
+// recounts errors and warnings. It returns the entries that no longer occur
+// at all, which means the baseline can be tightened.
+func (b *Baseline) Apply(result *ValidationResult) []BaselineEntry {
+	remaining := make(map[baselineKey]int, len(b.Issues))
+	for _, entry := range b.Issues {
+		remaining[baselineKey{entry.Rule, entry.File, entry.Message}] += entry.Count
+	}
+	matched := make(map[baselineKey]bool)
+
+	kept := make([]Issue, 0, len(result.Issues))
+	result.ErrorCount, result.WarningCount = 0, 0
+	for _, issue := range result.Issues {
+		key := baselineKey{issue.Rule, issue.File, issue.Message}
+		if remaining[key] > 0 {
+			remaining[key]--
+			matched[key] = true
+			result.BaselineCount++
+			continue
+		}
+
+		kept = append(kept, issue)
+		if issue.Level == "error" {
+			result.ErrorCount++
+		} else {
+			result.WarningCount++
+		}
+	}
+	result.Issues = kept
+
+	var stale []BaselineEntry
+	for _, entry := range b.Issues {
+		if !matched[baselineKey{entry.Rule, entry.File, entry.Message}] {
+			stale = append(stale, entry)
+		}
+	}
+	return stale
+}
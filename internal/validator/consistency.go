@@ -0,0 +1,251 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/adrref"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+)
+
+var (
+	// deprecationHeadingPattern matches headings that explain a deprecation
+	deprecationHeadingPattern = regexp.MustCompile(`(?i)^#{2,6}\s+.*deprecat`)
+
+	// deprecationEntryPattern matches a status history entry with a reason,
+	// as written by "adr-gen status 7 deprecated --reason ..."
+	deprecationEntryPattern = regexp.MustCompile(`(?i)^[-*]\s+.*→\s*deprecated\b[^:]*:\s*\S`)
+)
+
+// record is what the cross-ADR rules need to know about one ADR
+type record struct {
+	file         adrfs.File
+	number       string
+	lines        []string // Content with front matter blanked out
+	status       string
+	statusLine   int
+	supersedes   map[string]bool // Numbers this ADR says it replaces
+	supersededBy []string        // Numbers this ADR says replace it
+	dependsOn    []string
+	explained    bool // A deprecation reason is present
+}
+
+// validateConsistency checks relationships across the whole log:
+// supersession links in both directions, deprecation reasons and
+// dependencies of accepted decisions
+func (v *Validator) validateConsistency(files []adrfs.File, result *ValidationResult) error {
+	records := make(map[string]*record, len(files))
+	var order []*record
+	for _, file := range files {
+		rec, err := loadRecord(file)
+		if err != nil {
+			return err
+		}
+		if _, exists := records[rec.number]; !exists {
+			records[rec.number] = rec
+		}
+		order = append(order, rec)
+	}
+
+	for _, rec := range order {
+		fileResult := &ValidationResult{}
+		report := func(rule string, format string, args ...interface{}) {
+			fileResult.Issues = append(fileResult.Issues, Issue{
+				Rule:    rule,
+				File:    rec.file.RelPath(),
+				Line:    rec.statusLine,
+				Level:   "error",
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		// A Superseded ADR names an existing, decided replacement
+		if strings.EqualFold(rec.status, "Superseded") {
+			if len(rec.supersededBy) == 0 {
+				report(RuleSupersededTarget, "Superseded ADR must link to the ADR that supersedes it, e.g. \"Superseded by ADR-NNNN\"")
+			}
+			for _, number := range rec.supersededBy {
+				target, ok := records[number]
+				switch {
+				case !ok:
+					report(RuleSupersededTarget, "Superseded by ADR-%s, which does not exist", number)
+				case strings.EqualFold(target.status, "Proposed"):
+					report(RuleSupersededTarget, "Superseded by ADR-%s, which is still Proposed", number)
+				}
+			}
+		}
+
+		// Both sides of a supersession reference each other. A Proposed
+		// replacement supersedes nothing yet; accepting it adds the link back.
+		for _, number := range rec.supersededBy {
+			if target, ok := records[number]; ok && !target.supersedes[rec.number] {
+				report(RuleSupersedesBacklink, "ADR-%s does not reference this ADR back; add \"Supersedes ADR-%s\" to %s",
+					number, rec.number, target.file.RelPath())
+			}
+		}
+		if !strings.EqualFold(rec.status, "Proposed") {
+			for _, number := range sortedKeys(rec.supersedes) {
+				if target, ok := records[number]; ok && !containsString(target.supersededBy, rec.number) {
+					report(RuleSupersedesBacklink, "ADR-%s does not reference this ADR back; add \"Superseded by ADR-%s\" to %s",
+						number, rec.number, target.file.RelPath())
+				}
+			}
+		}
+
+		// Deprecated ADRs say why
+		if strings.EqualFold(rec.status, "Deprecated") && !rec.explained {
+			report(RuleDeprecationReason, "Deprecated ADR must explain why: add a \"Why This Decision Was Deprecated\" section or a reason under Status")
+		}
+
+		// Accepted decisions do not build on deprecated ones
+		if strings.EqualFold(rec.status, "Accepted") {
+			for _, number := range rec.dependsOn {
+				if target, ok := records[number]; ok && strings.EqualFold(target.status, "Deprecated") {
+					report(RuleDeprecatedDependency, "Accepted ADR depends on ADR-%s, which is Deprecated", number)
+				}
+			}
+		}
+
+		fileResult.ErrorCount = len(fileResult.Issues)
+		v.applyRules(rec.lines, fileResult)
+		result.merge(fileResult)
+	}
+	return nil
+}
+
+// loadRecord reads the status and relationships of an ADR
+func loadRecord(file adrfs.File) (*record, error) {
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.RelPath(), err)
+	}
+	content := string(data)
+
+	rec := &record{
+		file:       file,
+		number:     file.Name[:4],
+		supersedes: make(map[string]bool),
+	}
+
+	// Front matter parse errors are reported by the front-matter rule
+	fm, body, _ := frontmatter.Parse(content)
+	rec.lines = blankFrontMatter(content)
+
+	rec.status, rec.statusLine = documentStatus(fm, rec.lines)
+
+	prose := adrref.Supersessions(body)
+	var supersedes, supersededBy, dependsOn []string
+	if fm != nil {
+		supersedes = append(supersedes, fm.Supersedes...)
+		supersededBy = append(supersededBy, fm.SupersededBy...)
+		dependsOn = append(dependsOn, fm.DependsOn...)
+	}
+	supersedes = append(supersedes, prose[adrref.Supersedes]...)
+	supersededBy = append(supersededBy, prose[adrref.SupersededBy]...)
+	dependsOn = append(dependsOn, adrref.Related(body)[adrref.DependsOn]...)
+
+	for _, ref := range supersedes {
+		if number := adrref.NormalizeNumber(ref); number != "" && number != rec.number {
+			rec.supersedes[number] = true
+		}
+	}
+	rec.supersededBy = normalizeRefs(supersededBy, rec.number)
+	rec.dependsOn = normalizeRefs(dependsOn, rec.number)
+	rec.explained = explainsDeprecation(rec.lines)
+	return rec, nil
+}
+
+// blankFrontMatter splits content into lines with the front matter block
+// blanked out, so that line numbers still match the file
+func blankFrontMatter(content string) []string {
+	lines := strings.Split(content, "\n")
+	if _, _, blockLines, ok := frontmatter.Split(content); ok {
+		for i := 0; i < blockLines && i < len(lines); i++ {
+			lines[i] = ""
+		}
+	}
+	return lines
+}
+
+// documentStatus returns an ADR's status and the line declaring it. Front
+// matter wins over the first line of the ## Status section.
+func documentStatus(fm *frontmatter.FrontMatter, lines []string) (string, int) {
+	if fm != nil && fm.Status != "" {
+		return fm.Status, 1
+	}
+
+	inStatus, inCode := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			inStatus = strings.EqualFold(strings.TrimSpace(strings.TrimLeft(trimmed, "#")), "Status")
+			continue
+		}
+		if inStatus && trimmed != "" {
+			return trimmed, i + 1
+		}
+	}
+	return "", 0
+}
+
+// explainsDeprecation reports whether an ADR has a heading about its
+// deprecation, a reason written under its status or a reason recorded in
+// its status history
+func explainsDeprecation(lines []string) bool {
+	inStatus, seenValue := false, false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if deprecationHeadingPattern.MatchString(trimmed) || deprecationEntryPattern.MatchString(trimmed) {
+			return true
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			inStatus = strings.EqualFold(strings.TrimSpace(strings.TrimLeft(trimmed, "#")), "Status")
+			continue
+		}
+		if !inStatus || trimmed == "" {
+			continue
+		}
+		if seenValue {
+			return true // Text after the status value
+		}
+		seenValue = true
+	}
+	return false
+}
+
+// normalizeRefs converts references to unique four-digit numbers, dropping
+// references to self
+func normalizeRefs(refs []string, self string) []string {
+	seen := make(map[string]bool)
+	var numbers []string
+	for _, ref := range refs {
+		number := adrref.NormalizeNumber(ref)
+		if number == "" || number == self || seen[number] {
+			continue
+		}
+		seen[number] = true
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
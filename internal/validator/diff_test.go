@@ -0,0 +1,60 @@
+package validator
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "no change",
+			before: "a\nb\n",
+			after:  "a\nb\n",
+			want:   "--- a/x.md\n+++ b/x.md\n",
+		},
+		{
+			name:   "changed line with context",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9",
+			after:  "1\n2\n3\n4\nfive\n6\n7\n8\n9",
+			want: "--- a/x.md\n+++ b/x.md\n@@ -2,7 +2,7 @@\n" +
+				" 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:   "insertion at the start",
+			before: "b\nc",
+			after:  "a\nb\nc",
+			want:   "--- a/x.md\n+++ b/x.md\n@@ -1,2 +1,3 @@\n+a\n b\n c\n",
+		},
+		{
+			name:   "appended line",
+			before: "a",
+			after:  "a\nb",
+			want:   "--- a/x.md\n+++ b/x.md\n@@ -1,1 +1,2 @@\n a\n+b\n",
+		},
+		{
+			name:   "distant changes in separate hunks",
+			before: "a\n1\n2\n3\n4\n5\n6\n7\nb",
+			after:  "A\n1\n2\n3\n4\n5\n6\n7\nB",
+			want: "--- a/x.md\n+++ b/x.md\n" +
+				"@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+B\n",
+		},
+		{
+			name:   "nearby changes share a hunk",
+			before: "a\n1\n2\nb",
+			after:  "A\n1\n2\nB",
+			want:   "--- a/x.md\n+++ b/x.md\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n-b\n+B\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("x.md", tt.before, tt.after); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrformat"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+)
+
+// FixFunc returns content with the issues of a single rule corrected.
+// Fixes must be safe: they never remove author-written text.
+type FixFunc func(v *Validator, content string) string
+
+// fixers maps rule identifiers to their automatic fix
+var fixers = map[string]FixFunc{
+	RuleMermaidFence:       fixMermaidFence,
+	RuleRequiredSections:   fixRequiredSections,
+	RuleAllowedStatus:      fixStatusCase,
+	RuleAllowedCategory:    fixCategoryCase,
+	RuleTerminology:        fixTerminology,
+	RuleTrailingWhitespace: fixTrailingWhitespace,
+}
+
+// fixOrder is the order fixes are applied in. Fences are closed first so
+// inserted sections never land inside a diagram, and whitespace is trimmed
+// last so it also covers inserted text.
+var fixOrder = []string{
+	RuleMermaidFence,
+	RuleRequiredSections,
+	RuleAllowedStatus,
+	RuleAllowedCategory,
+	RuleTerminology,
+	RuleTrailingWhitespace,
+}
+
+// applyFixes runs the fix for every rule that reported an issue. It
+// returns the fixed content and the number of issues whose fix changed it.
+func (v *Validator) applyFixes(content string, issues []Issue) (string, int) {
+	reported := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Fixable {
+			reported[issue.Rule]++
+		}
+	}
+
+	fixed := 0
+	for _, rule := range fixOrder {
+		if reported[rule] == 0 {
+			continue
+		}
+		if updated := fixers[rule](v, content); updated != content {
+			content = updated
+			fixed += reported[rule]
+		}
+	}
+	return content, fixed
+}
+
+// fixTrailingWhitespace strips spaces and tabs at the end of every line
+func fixTrailingWhitespace(v *Validator, content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fixMermaidFence closes an unclosed mermaid block before the next heading
+func fixMermaidFence(v *Validator, content string) string {
+	lines := strings.Split(content, "\n")
+	start := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "```mermaid" {
+			start = i
+		} else if trimmed == "```" && start >= 0 {
+			start = -1
+		}
+	}
+	if start < 0 {
+		return content
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "#") {
+			end = i
+			break
+		}
+	}
+	// Place the fence directly after the last diagram line
+	for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	return strings.Join(insertLines(lines, end, []string{"```"}), "\n")
+}
+
+// fixRequiredSections inserts the sections missing for the ADR's format in
+// the canonical order. Only the heading is inserted, apart from the default
+// status, so that no template prose ends up in the ADR for the author to
+// remove again.
+func fixRequiredSections(v *Validator, content string) string {
+	fm, body, _ := frontmatter.Parse(content)
+	format := v.formatFor(fm, body)
+	if format == nil {
+		return content
+	}
+
+	order := sectionOrder(format)
+	for _, section := range format.RequiredSections {
+		lines := strings.Split(content, "\n")
+		positions := sectionPositions(lines)
+		if _, ok := positions[strings.ToLower(section)]; ok {
+			continue
+		}
+
+		// Insert before the next section of the template that exists
+		at := -1
+		for _, later := range sectionsAfter(order, section) {
+			if pos, ok := positions[strings.ToLower(later)]; ok {
+				at = pos
+				break
+			}
+		}
+		if at < 0 {
+			at = documentEnd(lines)
+		}
+
+		block := []string{"## " + section}
+		if strings.EqualFold(section, "Status") {
+			block = append(block, "", v.defaultStatus())
+		}
+		if at > 0 && strings.TrimSpace(lines[at-1]) != "" {
+			block = append([]string{""}, block...)
+		}
+		if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+			block = append(block, "")
+		}
+		content = strings.Join(insertLines(lines, at, block), "\n")
+	}
+
+	return content
+}
+
+// sectionOrder lists the H2 headings of the format's template in order,
+// followed by any required sections the template lacks
+func sectionOrder(format *adrformat.Format) []string {
+	var order []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(format.Template, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "## ") {
+			name := strings.TrimSpace(trimmed[3:])
+			order = append(order, name)
+			seen[strings.ToLower(name)] = true
+		}
+	}
+	for _, section := range format.RequiredSections {
+		if !seen[strings.ToLower(section)] {
+			order = append(order, section)
+		}
+	}
+	return order
+}
+
+// sectionsAfter returns the sections that follow section in order
+func sectionsAfter(order []string, section string) []string {
+	for i, name := range order {
+		if strings.EqualFold(name, section) {
+			return order[i+1:]
+		}
+	}
+	return nil
+}
+
+// fixStatusCase rewrites status values to the configured spelling
+func fixStatusCase(v *Validator, content string) string {
+	return fixValueCase(content, "status", v.config.Project.AllowedStatuses)
+}
+
+// fixCategoryCase rewrites category values to the configured spelling
+func fixCategoryCase(v *Validator, content string) string {
+	return fixValueCase(content, "category", v.config.Project.AllowedCategories)
+}
+
+// fixValueCase normalizes the case of a "## Kind" section value, a
+// "Kind:" line or a front matter key when it matches an allowed value
+func fixValueCase(content, kind string, allowed []string) string {
+	lines := strings.Split(content, "\n")
+	prefix := kind + ":"
+	inSection := false
+	inCode := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "## ") {
+			inSection = strings.EqualFold(strings.TrimSpace(trimmed[3:]), kind)
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(trimmed), prefix) {
+			value := strings.Trim(strings.TrimSpace(trimmed[len(prefix):]), `"'`)
+			if canonical, ok := canonicalValue(value, allowed); ok && canonical != value {
+				lines[i] = strings.Replace(line, value, canonical, 1)
+			}
+			continue
+		}
+
+		if inSection {
+			if canonical, ok := canonicalValue(trimmed, allowed); ok && canonical != trimmed {
+				lines[i] = strings.Replace(line, trimmed, canonical, 1)
+			}
+			inSection = false
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// canonicalValue returns the allowed spelling of value, ignoring case
+func canonicalValue(value string, allowed []string) (string, bool) {
+	for _, candidate := range allowed {
+		if strings.EqualFold(value, candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// defaultStatus returns the status used for an inserted Status section
+func (v *Validator) defaultStatus() string {
+	if v.config.Project.IsValidStatus("Proposed") || len(v.config.Project.AllowedStatuses) == 0 {
+		return "Proposed"
+	}
+	return v.config.Project.AllowedStatuses[0]
+}
+
+// sectionPositions maps lower-cased H2 headings to their line index
+func sectionPositions(lines []string) map[string]int {
+	positions := make(map[string]int)
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if !inCode && strings.HasPrefix(trimmed, "## ") {
+			name := strings.ToLower(strings.TrimSpace(trimmed[3:]))
+			if _, seen := positions[name]; !seen {
+				positions[name] = i
+			}
+		}
+	}
+	return positions
+}
+
+// documentEnd returns where appended sections belong: before a trailing
+// "---" footer if there is one, otherwise after the last non-blank line
+func documentEnd(lines []string) int {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	for i := end - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		if trimmed == "---" {
+			return i
+		}
+	}
+	return end
+}
+
+// insertLines returns lines with block inserted at index at
+func insertLines(lines []string, at int, block []string) []string {
+	out := make([]string, 0, len(lines)+len(block))
+	out = append(out, lines[:at]...)
+	out = append(out, block...)
+	return append(out, lines[at:]...)
+}
@@ -0,0 +1,89 @@
+package validator
+
+import "testing"
+
+func TestFixers(t *testing.T) {
+	v := New(&Config{})
+
+	tests := []struct {
+		name    string
+		fix     FixFunc
+		content string
+		want    string
+	}{
+		{
+			name:    "trailing whitespace",
+			fix:     fixTrailingWhitespace,
+			content: "# Title  \n\nText\t\n",
+			want:    "# Title\n\nText\n",
+		},
+		{
+			name:    "mermaid fence closed before the next heading",
+			fix:     fixMermaidFence,
+			content: "## Decision\n\n```mermaid\ngraph TD\n  A --> B\n\n## Consequences\n",
+			want:    "## Decision\n\n```mermaid\ngraph TD\n  A --> B\n```\n\n## Consequences\n",
+		},
+		{
+			name:    "closed mermaid fence untouched",
+			fix:     fixMermaidFence,
+			content: "```mermaid\ngraph TD\n```\n",
+			want:    "```mermaid\ngraph TD\n```\n",
+		},
+		{
+			name:    "missing sections inserted in template order",
+			fix:     fixRequiredSections,
+			content: "# Title\n\n## Context\n\nWhy.\n\n## Decision\n\nWhat.\n",
+			want:    "# Title\n\n## Status\n\nProposed\n\n## Context\n\nWhy.\n\n## Decision\n\nWhat.\n\n## Consequences\n",
+		},
+		{
+			name:    "status case",
+			fix:     fixStatusCase,
+			content: "## Status\n\naccepted\n",
+			want:    "## Status\n\nAccepted\n",
+		},
+		{
+			name:    "status case in front matter",
+			fix:     fixStatusCase,
+			content: "---\nstatus: \"deprecated\"\n---\n",
+			want:    "---\nstatus: \"Deprecated\"\n---\n",
+		},
+		{
+			name:    "unknown status left alone",
+			fix:     fixStatusCase,
+			content: "## Status\n\nApproved\n",
+			want:    "## Status\n\nApproved\n",
+		},
+		{
+			name:    "code blocks left alone",
+			fix:     fixStatusCase,
+			content: "```\nstatus: accepted\n```\n",
+			want:    "```\nstatus: accepted\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fix(v, tt.content); got != tt.want {
+				t.Errorf("fix() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFixesCountsChangedIssues(t *testing.T) {
+	v := New(&Config{})
+	content := "# Title  \n\n## Status\n\nProposed\n"
+	issues := []Issue{
+		{Rule: RuleTrailingWhitespace, Fixable: true},
+		{Rule: RuleAllowedStatus, Fixable: true}, // Already canonical, so nothing changes
+		{Rule: RuleMermaidFence, Fixable: false},
+	}
+
+	got, fixed := v.applyFixes(content, issues)
+	if want := "# Title\n\n## Status\n\nProposed\n"; got != want {
+		t.Errorf("applyFixes() content = %q, want %q", got, want)
+	}
+	if fixed != 1 {
+		t.Errorf("applyFixes() fixed = %d, want 1", fixed)
+	}
+}
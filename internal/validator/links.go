@@ -0,0 +1,192 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+	"github.com/euforicio/adr-demo/internal/markdown"
+)
+
+var (
+	// inlineLinkPattern matches [text](target "title") and ![alt](target)
+	inlineLinkPattern = regexp.MustCompile(`(!?)\[(?:[^\]\\]|\\.)*\]\(\s*(<[^>]*>|[^)\s]+)(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*\)`)
+
+	// referenceDefPattern matches [label]: target reference definitions
+	referenceDefPattern = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s*(<[^>]*>|\S+)`)
+
+	// inlineCodePattern matches code spans, whose contents are not links
+	inlineCodePattern = regexp.MustCompile("`+[^`]*`+")
+
+	// schemePattern matches absolute URLs such as https: or mailto:
+	schemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+	// sitePagePattern matches links to generated ADR pages, e.g. /adr-0009.html
+	sitePagePattern = regexp.MustCompile(`(?:^|/)adr-([0-9]{4})\.html$`)
+)
+
+// link is a link or image target found in an ADR
+type link struct {
+	Target string
+	Image  bool
+	Line   int // 1-based
+	Column int // 1-based column of the target
+}
+
+// validateLinks checks that relative links and images point at existing
+// files, and that #anchors match the heading IDs goldmark generates
+func (v *Validator) validateLinks(filename string, lines []string, result *ValidationResult) {
+	adrDir := v.config.Project.ADRDirectory
+	baseDir := filepath.Join(adrDir, filepath.Dir(filepath.FromSlash(filename)))
+	ownIDs := markdown.HeadingIDs(strings.Join(lines, "\n"))
+
+	for _, l := range extractLinks(lines) {
+		if message := v.checkLink(l, baseDir, ownIDs); message != "" {
+			result.Issues = append(result.Issues, Issue{
+				Rule:    RuleInternalLinks,
+				File:    filename,
+				Line:    l.Line,
+				Column:  l.Column,
+				Level:   "error",
+				Message: message,
+			})
+			result.ErrorCount++
+		}
+	}
+}
+
+// checkLink resolves a single link and describes the problem, if any
+func (v *Validator) checkLink(l link, baseDir string, ownIDs map[string]bool) string {
+	target := strings.Trim(l.Target, "<>")
+	if target == "" || schemePattern.MatchString(target) || strings.HasPrefix(target, "//") {
+		return "" // External links are not checked
+	}
+
+	pathPart, fragment, _ := strings.Cut(target, "#")
+	if decoded, err := url.PathUnescape(pathPart); err == nil {
+		pathPart = decoded
+	}
+
+	// Same-page anchor
+	if pathPart == "" {
+		if fragment != "" && !ownIDs[fragment] {
+			return fmt.Sprintf("Broken anchor #%s: no heading with that ID in this ADR", fragment)
+		}
+		return ""
+	}
+
+	// Links to generated pages, e.g. /adr-0009.html
+	if match := sitePagePattern.FindStringSubmatch(pathPart); match != nil {
+		if _, ok := v.adrByNumber(match[1]); !ok {
+			return fmt.Sprintf("Broken link %s: there is no ADR-%s", target, match[1])
+		}
+		return ""
+	}
+	if strings.HasPrefix(pathPart, "/") {
+		return "" // Other site-absolute URLs cannot be resolved from the repository
+	}
+
+	resolved := filepath.Join(baseDir, filepath.FromSlash(pathPart))
+	info, err := os.Stat(resolved)
+	if err != nil {
+		kind := "link"
+		if l.Image {
+			kind = "image"
+		}
+		message := fmt.Sprintf("Broken %s %s: %s does not exist", kind, target, filepath.ToSlash(resolved))
+		if hint := v.linkHint(pathPart, baseDir); hint != "" {
+			message += fmt.Sprintf(" (did you mean %s?)", hint)
+		}
+		return message
+	}
+
+	if fragment == "" || info.IsDir() || !strings.HasSuffix(resolved, ".md") {
+		return ""
+	}
+	if !v.headingIDs(resolved)[fragment] {
+		return fmt.Sprintf("Broken anchor %s: %s has no heading with ID %q", target, filepath.Base(resolved), fragment)
+	}
+	return ""
+}
+
+// linkHint suggests the current path, relative to baseDir, of an ADR that
+// was renamed or moved
+func (v *Validator) linkHint(pathPart, baseDir string) string {
+	name := filepath.Base(pathPart)
+	if len(name) < 4 {
+		return ""
+	}
+	file, ok := v.adrByNumber(name[:4])
+	if !ok {
+		return ""
+	}
+	rel, err := filepath.Rel(baseDir, file.Path)
+	if err != nil || filepath.ToSlash(rel) == pathPart {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// headingIDs returns the heading IDs of a markdown file, caching the result
+func (v *Validator) headingIDs(path string) map[string]bool {
+	if ids, ok := v.anchors[path]; ok {
+		return ids
+	}
+
+	ids := map[string]bool{}
+	if data, err := os.ReadFile(path); err == nil {
+		content := string(data)
+		if _, body, _, ok := frontmatter.Split(content); ok {
+			content = body
+		}
+		ids = markdown.HeadingIDs(content)
+	}
+	v.anchors[path] = ids
+	return ids
+}
+
+// extractLinks finds inline links, images and reference definitions outside
+// of code blocks and code spans
+func extractLinks(lines []string) []link {
+	var links []link
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		// Blank out code spans so columns stay accurate
+		masked := inlineCodePattern.ReplaceAllStringFunc(line, func(span string) string {
+			return strings.Repeat(" ", len(span))
+		})
+
+		if match := referenceDefPattern.FindStringSubmatchIndex(masked); match != nil {
+			links = append(links, link{
+				Target: masked[match[2]:match[3]],
+				Line:   i + 1,
+				Column: utf8.RuneCountInString(masked[:match[2]]) + 1,
+			})
+			continue
+		}
+
+		for _, match := range inlineLinkPattern.FindAllStringSubmatchIndex(masked, -1) {
+			links = append(links, link{
+				Target: masked[match[4]:match[5]],
+				Image:  match[3] > match[2],
+				Line:   i + 1,
+				Column: utf8.RuneCountInString(masked[:match[4]]) + 1,
+			})
+		}
+	}
+	return links
+}
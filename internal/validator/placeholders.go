@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrformat"
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+)
+
+var (
+	// templateVarPattern matches template variables such as {{title}}
+	templateVarPattern = regexp.MustCompile(`\{\{\s*[a-z_]+\s*\}\}`)
+
+	// bracketPlaceholderPattern matches lines that are a single bracketed
+	// instruction, such as "[Short noun phrase]", but not links
+	bracketPlaceholderPattern = regexp.MustCompile(`^\[[^\]]+\]$`)
+)
+
+// validatePlaceholders flags template boilerplate left in an ADR. Accepted
+// ADRs get errors so half-written decisions cannot be accepted; other
+// statuses get warnings.
+func (v *Validator) validatePlaceholders(filename string, lines []string, fm *frontmatter.FrontMatter, result *ValidationResult) {
+	format := v.formatFor(fm, strings.Join(lines, "\n"))
+	if format == nil {
+		return
+	}
+	fingerprints := v.placeholders(format)
+
+	level := "warning"
+	status, _ := documentStatus(fm, lines)
+	if strings.EqualFold(status, "Accepted") {
+		level = "error"
+	}
+
+	inCode := false
+	for lineNum, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || trimmed == "" {
+			continue
+		}
+
+		message := ""
+		switch {
+		case fingerprints[normalizePlaceholder(trimmed)]:
+			message = fmt.Sprintf("Unfilled template placeholder: %q", shorten(trimmed, 60))
+		case templateVarPattern.MatchString(trimmed):
+			message = fmt.Sprintf("Unrendered template variable %s", templateVarPattern.FindString(trimmed))
+		case bracketPlaceholderPattern.MatchString(trimmed):
+			message = fmt.Sprintf("Unfilled template placeholder: %q", shorten(trimmed, 60))
+		default:
+			continue
+		}
+
+		result.Issues = append(result.Issues, Issue{
+			Rule:    RulePlaceholders,
+			File:    filename,
+			Line:    lineNum + 1,
+			Column:  len(line) - len(strings.TrimLeft(line, " \t")) + 1,
+			Level:   level,
+			Message: message,
+		})
+		if level == "error" {
+			result.ErrorCount++
+		} else {
+			result.WarningCount++
+		}
+	}
+}
+
+// placeholders fingerprints the boilerplate of the templates an ADR in
+// format could have been created from: the format's own template and, for
+// the default format, the repository's template.md. Every line that is not a
+// heading, a template variable or a separator counts as boilerplate.
+func (v *Validator) placeholders(format *adrformat.Format) map[string]bool {
+	if cached, ok := v.fingerprints[format.Name]; ok {
+		return cached
+	}
+
+	templates := []string{format.Template}
+	if format.Name == v.config.Project.DefaultFormat {
+		templatePath := filepath.Join(v.config.Project.ADRDirectory, adrfs.TemplateFile)
+		if data, err := os.ReadFile(templatePath); err == nil {
+			templates = append(templates, string(data))
+		}
+	}
+
+	fingerprints := make(map[string]bool)
+	for _, tmpl := range templates {
+		if _, body, _, ok := frontmatter.Split(tmpl); ok {
+			tmpl = body
+		}
+		for _, line := range strings.Split(tmpl, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || trimmed == "---" || strings.HasPrefix(trimmed, "#") || templateVarPattern.MatchString(trimmed) {
+				continue
+			}
+			fingerprints[normalizePlaceholder(trimmed)] = true
+		}
+	}
+
+	v.fingerprints[format.Name] = fingerprints
+	return fingerprints
+}
+
+// normalizePlaceholder collapses whitespace and case so reflowed or
+// re-indented boilerplate still matches
+func normalizePlaceholder(line string) string {
+	return strings.ToLower(strings.Join(strings.Fields(line), " "))
+}
+
+// shorten truncates s to at most n runes, marking the cut with an ellipsis
+func shorten(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,349 @@
+package validator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Report formats accepted by WriteReport
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatSARIF  = "sarif"
+	FormatJUnit  = "junit"
+	FormatGitHub = "github"
+)
+
+// ReportFormats lists the supported report formats
+var ReportFormats = []string{FormatText, FormatJSON, FormatSARIF, FormatJUnit, FormatGitHub}
+
+// WriteReport writes the issues in result in a machine-readable format.
+// baseDir is the ADR directory, used to turn issue paths into
+// repository-relative paths. The text format is rendered by the CLI.
+func WriteReport(w io.Writer, format string, result *ValidationResult, baseDir string) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, result, baseDir)
+	case FormatSARIF:
+		return writeSARIF(w, result, baseDir)
+	case FormatJUnit:
+		return writeJUnit(w, result, baseDir)
+	case FormatGitHub:
+		return writeGitHub(w, result, baseDir)
+	default:
+		return fmt.Errorf("unknown report format %q (valid: %s)", format, strings.Join(ReportFormats, ", "))
+	}
+}
+
+// issuePath returns the slash-separated path of an issue's file. Issues
+// that are not tied to a markdown file apply to the ADR directory.
+func issuePath(baseDir, file string) string {
+	if file == "" || !strings.HasSuffix(file, ".md") {
+		return filepath.ToSlash(baseDir)
+	}
+	return filepath.ToSlash(filepath.Join(baseDir, file))
+}
+
+// jsonReport is the structure of the JSON report
+type jsonReport struct {
+	Summary jsonSummary `json:"summary"`
+	Issues  []jsonIssue `json:"issues"`
+}
+
+type jsonSummary struct {
+	Files     int `json:"files"`
+	Diagrams  int `json:"diagrams"`
+	Errors    int `json:"errors"`
+	Warnings  int `json:"warnings"`
+	Fixed     int `json:"fixed"`
+	Baselined int `json:"baselined"`
+}
+
+type jsonIssue struct {
+	Rule       string `json:"rule"`
+	Severity   string `json:"severity"`
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	Message    string `json:"message"`
+	Fixable    bool   `json:"fixable"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// writeJSON writes the result as a single JSON document
+func writeJSON(w io.Writer, result *ValidationResult, baseDir string) error {
+	report := jsonReport{
+		Summary: jsonSummary{
+			Files:     result.FileCount,
+			Diagrams:  result.DiagramCount,
+			Errors:    result.ErrorCount,
+			Warnings:  result.WarningCount,
+			Fixed:     result.FixCount,
+			Baselined: result.BaselineCount,
+		},
+		Issues: []jsonIssue{},
+	}
+	for _, issue := range result.Issues {
+		report.Issues = append(report.Issues, jsonIssue{
+			Rule:       issue.Rule,
+			Severity:   issue.Level,
+			File:       issuePath(baseDir, issue.File),
+			Line:       issue.Line,
+			Column:     issue.Column,
+			Message:    issue.Message,
+			Fixable:    issue.Fixable,
+			Suggestion: issue.Suggestion,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// SARIF 2.1.0 structures, limited to the properties adr-gen fills in
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifProperties struct {
+	Fixable bool `json:"fixable"`
+}
+
+// writeSARIF writes the result as a SARIF 2.1.0 log for code scanning
+func writeSARIF(w io.Writer, result *ValidationResult, baseDir string) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "adr-gen"}},
+		Results: []sarifResult{},
+	}
+
+	// Describe every rule that reported an issue
+	seen := make(map[string]bool)
+	for _, issue := range result.Issues {
+		if seen[issue.Rule] {
+			continue
+		}
+		seen[issue.Rule] = true
+		description := issue.Rule
+		if info, ok := ruleInfo(issue.Rule); ok {
+			description = info.Description
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               issue.Rule,
+			ShortDescription: sarifMessage{Text: description},
+		})
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+	if run.Tool.Driver.Rules == nil {
+		run.Tool.Driver.Rules = []sarifRule{}
+	}
+
+	for _, issue := range result.Issues {
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: issuePath(baseDir, issue.File)}}
+		if issue.Line > 0 {
+			location.Region = &sarifRegion{StartLine: issue.Line, StartColumn: issue.Column}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:     issue.Rule,
+			Level:      issue.Level,
+			Message:    sarifMessage{Text: withSuggestion(issue)},
+			Locations:  []sarifLocation{{PhysicalLocation: location}},
+			Properties: sarifProperties{Fixable: issue.Fixable},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// JUnit XML structures
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes one test suite per file with issues and one test case
+// per issue. Errors are failures; warnings pass with their message in
+// system-out.
+func writeJUnit(w io.Writer, result *ValidationResult, baseDir string) error {
+	suites := junitSuites{Name: "adr-gen validate"}
+	index := make(map[string]int)
+
+	for _, issue := range result.Issues {
+		file := issuePath(baseDir, issue.File)
+		i, ok := index[file]
+		if !ok {
+			i = len(suites.Suites)
+			index[file] = i
+			suites.Suites = append(suites.Suites, junitSuite{Name: file})
+		}
+
+		location := file
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", file, issue.Line)
+			if issue.Column > 0 {
+				location += fmt.Sprintf(":%d", issue.Column)
+			}
+		}
+		details := fmt.Sprintf("%s: %s [%s]", location, withSuggestion(issue), issue.Level)
+		if issue.Fixable {
+			details += " (fixable with --fix)"
+		}
+
+		testCase := junitCase{Name: fmt.Sprintf("%s %s", issue.Rule, location), Classname: issue.Rule}
+		if issue.Level == "error" {
+			testCase.Failure = &junitFailure{Type: issue.Rule, Message: issue.Message, Text: details}
+			suites.Suites[i].Failures++
+			suites.Failures++
+		} else {
+			testCase.SystemOut = details
+		}
+		suites.Suites[i].Cases = append(suites.Suites[i].Cases, testCase)
+		suites.Suites[i].Tests++
+		suites.Tests++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeGitHub writes GitHub Actions workflow commands, which show up as
+// annotations on the pull request diff
+func writeGitHub(w io.Writer, result *ValidationResult, baseDir string) error {
+	for _, issue := range result.Issues {
+		command := "warning"
+		if issue.Level == "error" {
+			command = "error"
+		}
+
+		properties := []string{"file=" + escapeProperty(issuePath(baseDir, issue.File))}
+		if issue.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", issue.Line))
+			if issue.Column > 0 {
+				properties = append(properties, fmt.Sprintf("col=%d", issue.Column))
+			}
+		}
+		properties = append(properties, "title="+escapeProperty(issue.Rule))
+
+		message := withSuggestion(issue)
+		if issue.Fixable {
+			message += " (fixable with adr-gen validate --fix)"
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(properties, ","), escapeData(message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withSuggestion appends the suggested manual fix, if any, to the message
+func withSuggestion(issue Issue) string {
+	if issue.Suggestion == "" {
+		return issue.Message
+	}
+	return fmt.Sprintf("%s. %s", strings.TrimSuffix(issue.Message, "."), issue.Suggestion)
+}
+
+// escapeData escapes a workflow command message
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,271 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/config"
+)
+
+// RuleInfo describes a validation rule
+type RuleInfo struct {
+	ID          string
+	Description string
+	Strict      bool                   // Enabled by default only with --strict
+	Params      map[string]interface{} // Parameters and their default values
+}
+
+// Rules is the registry of validation rules. IDs are stable: they appear in
+// reports, in the rules section of adr-config.yaml and in inline
+// <!-- adr-gen-disable rule-id --> comments.
+var Rules = []RuleInfo{
+	{ID: RuleFilenameFormat, Description: "ADR filenames follow NNNN-kebab-case-title.md"},
+	{ID: RuleNumbering, Description: "ADR numbers are sequential without gaps"},
+	{ID: RuleDuplicateNumber, Description: "Each ADR number is used by one file only"},
+	{ID: RuleSlugTitle, Description: "Filename slugs match the ADR title"},
+	{ID: RuleFrontMatter, Description: "Front matter is valid YAML with known values"},
+	{ID: RuleAllowedStatus, Description: "Status is one of the configured statuses"},
+	{ID: RuleAllowedCategory, Description: "Category is one of the configured categories"},
+	{
+		ID:          RuleRequiredSections,
+		Description: "The sections required by the ADR format are present",
+		Params:      map[string]interface{}{"sections": []string{}}, // Replaces the format's list when set
+	},
+	{ID: RulePlaceholders, Description: "Template placeholder text has been replaced"},
+	{ID: RuleTerminology, Description: "Glossary terms are used instead of their banned variants"},
+	{ID: RuleHeadingHierarchy, Description: "ADRs have a single, non-empty H1 title"},
+	{ID: RuleMermaidFence, Description: "Mermaid code fences are closed"},
+	{ID: RuleMermaidSyntax, Description: "Mermaid diagrams are syntactically valid"},
+	{ID: RuleInternalLinks, Description: "Internal links, anchors and images resolve"},
+	{
+		ID:          RuleLineLength,
+		Description: "Lines do not exceed the maximum length",
+		Strict:      true,
+		Params:      map[string]interface{}{"max": 120},
+	},
+	{ID: RuleTrailingWhitespace, Description: "Lines have no trailing whitespace", Strict: true},
+	{ID: RuleSupersededTarget, Description: "Superseded ADRs link to an existing, decided replacement"},
+	{ID: RuleSupersedesBacklink, Description: "Both ADRs of a supersession reference each other"},
+	{ID: RuleDeprecationReason, Description: "Deprecated ADRs explain why"},
+	{ID: RuleDeprecatedDependency, Description: "Accepted ADRs do not depend on Deprecated ones"},
+	{ID: RuleStatusTransition, Description: "Status changes since the base git revision follow status_transitions"},
+}
+
+// directivePattern matches inline <!-- adr-gen-disable rule-id --> and
+// <!-- adr-gen-enable rule-id --> comments. Without rule IDs they apply
+// to every rule.
+var directivePattern = regexp.MustCompile(`<!--\s*adr-gen-(disable|enable)((?:[\s,]+[a-z0-9-]+)*)\s*-->`)
+
+// ruleInfo returns the registry entry for a rule ID
+func ruleInfo(id string) (RuleInfo, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return RuleInfo{}, false
+}
+
+// CheckRules validates the rules section of the project configuration:
+// rule IDs must exist and parameters must match their defaults' types
+func CheckRules(project *config.Config) error {
+	ids := make([]string, 0, len(project.Rules))
+	for id := range project.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var problems []string
+	for _, id := range ids {
+		info, ok := ruleInfo(id)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown rule %q", id))
+			continue
+		}
+
+		params := make([]string, 0, len(project.Rules[id].Params))
+		for name := range project.Rules[id].Params {
+			params = append(params, name)
+		}
+		sort.Strings(params)
+		for _, name := range params {
+			def, ok := info.Params[name]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("unknown parameter %q for rule %q", name, id))
+				continue
+			}
+			if _, ok := convertParam(project.Rules[id].Params[name], def); !ok {
+				problems = append(problems, fmt.Sprintf("parameter %q of rule %q must be %s", name, id, describeParam(def)))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid rules configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// convertParam converts a configured value to the type of def
+func convertParam(value, def interface{}) (interface{}, bool) {
+	switch def.(type) {
+	case int:
+		n, ok := value.(int)
+		return n, ok && n > 0
+	case []string:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	default:
+		return nil, false
+	}
+}
+
+// describeParam names the expected type of a parameter for messages
+func describeParam(def interface{}) string {
+	switch def.(type) {
+	case int:
+		return "a positive number"
+	case []string:
+		return "a list of strings"
+	default:
+		return fmt.Sprintf("%T", def)
+	}
+}
+
+// RuleEnabled reports whether a rule runs. The rules configuration wins;
+// otherwise strict rules run only in strict mode.
+func (v *Validator) RuleEnabled(id string) bool {
+	if rule, ok := v.config.Project.Rules[id]; ok && rule.Enabled != nil {
+		return *rule.Enabled
+	}
+	info, _ := ruleInfo(id)
+	return !info.Strict || v.config.Strict
+}
+
+// param returns a rule parameter, falling back to its default
+func (v *Validator) param(id, name string) interface{} {
+	info, _ := ruleInfo(id)
+	def := info.Params[name]
+	if value, ok := v.config.Project.Rules[id].Params[name]; ok {
+		if converted, ok := convertParam(value, def); ok {
+			return converted
+		}
+	}
+	return def
+}
+
+// intParam returns a numeric rule parameter
+func (v *Validator) intParam(id, name string) int {
+	n, _ := v.param(id, name).(int)
+	return n
+}
+
+// stringsParam returns a list rule parameter
+func (v *Validator) stringsParam(id, name string) []string {
+	list, _ := v.param(id, name).([]string)
+	return list
+}
+
+// applyRules drops issues of disabled or inline-suppressed rules, applies
+// configured severities and recounts errors and warnings. lines may be nil
+// for issues that do not belong to a single file's content.
+func (v *Validator) applyRules(lines []string, result *ValidationResult) {
+	directives := parseDirectives(lines)
+
+	kept := make([]Issue, 0, len(result.Issues))
+	result.ErrorCount, result.WarningCount = 0, 0
+	for _, issue := range result.Issues {
+		if !v.RuleEnabled(issue.Rule) || directives.disabled(issue.Rule, issue.Line) {
+			continue
+		}
+		if severity := v.config.Project.Rules[issue.Rule].Severity; severity != "" {
+			issue.Level = severity
+		}
+
+		kept = append(kept, issue)
+		if issue.Level == "error" {
+			result.ErrorCount++
+		} else {
+			result.WarningCount++
+		}
+	}
+	result.Issues = kept
+}
+
+// directive is an inline adr-gen-disable or adr-gen-enable comment
+type directive struct {
+	line    int
+	disable bool
+	rules   []string // Empty means every rule
+}
+
+// directives are the inline comments of a file, in line order
+type directives []directive
+
+// parseDirectives finds the inline rule comments outside code blocks
+func parseDirectives(lines []string) directives {
+	var found directives
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		for _, match := range directivePattern.FindAllStringSubmatch(line, -1) {
+			found = append(found, directive{
+				line:    i + 1,
+				disable: match[1] == "disable",
+				rules:   strings.FieldsFunc(match[2], isDirectiveSeparator),
+			})
+		}
+	}
+	return found
+}
+
+// isDirectiveSeparator splits the rule list of a directive
+func isDirectiveSeparator(r rune) bool {
+	return r == ',' || r == ' ' || r == '\t'
+}
+
+// disabled reports whether rule is suppressed at line. A directive applies
+// from its own line onwards; issues without a line (0) belong to the whole
+// file and are suppressed by a directive still in effect at its end.
+func (d directives) disabled(rule string, line int) bool {
+	off := false
+	for _, dir := range d {
+		if line > 0 && dir.line > line {
+			break
+		}
+		if len(dir.rules) == 0 || containsString(dir.rules, rule) {
+			off = dir.disable
+		}
+	}
+	return off
+}
+
+// containsString reports whether list includes s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
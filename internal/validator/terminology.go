@@ -0,0 +1,264 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+)
+
+var (
+	// proseMaskPattern matches the parts of a line that are not prose: code
+	// spans, link destinations, HTML comments and tags, and bare URLs
+	proseMaskPattern = regexp.MustCompile("`[^`]*`|\\]\\([^)]*\\)|<!--.*?-->|<[^>]+>|https?://\\S+")
+
+	// articlePattern matches an indefinite article right before a term
+	articlePattern = regexp.MustCompile(`(?i)\b(a|an)\s+$`)
+)
+
+// glossaryPattern is a compiled glossary entry
+type glossaryPattern struct {
+	term      string
+	preferred *regexp.Regexp // The term itself, which is never flagged
+	variants  *regexp.Regexp // Any banned variant, with an optional plural suffix
+}
+
+// termMatch is a banned variant found in an ADR
+type termMatch struct {
+	line        int // Index into the ADR's lines
+	start, end  int // Byte offsets within the line
+	found       string
+	replacement string
+	unsafe      string // Why the replacement is not applied by --fix
+}
+
+// compileGlossary builds the patterns for every term that has variants
+func compileGlossary(glossary config.Glossary) []glossaryPattern {
+	var patterns []glossaryPattern
+	for _, term := range glossary.Terms() {
+		variants := append([]string{}, glossary[term].Variants...)
+		if len(variants) == 0 {
+			continue
+		}
+		// Longer variants first, so "micro service" wins over "micro"
+		sort.Slice(variants, func(i, j int) bool { return len(variants[i]) > len(variants[j]) })
+
+		alternatives := make([]string, len(variants))
+		for i, variant := range variants {
+			alternatives[i] = phrasePattern(variant)
+		}
+		patterns = append(patterns, glossaryPattern{
+			term:      term,
+			preferred: regexp.MustCompile(`(?i)` + phrasePattern(term) + `(?:e?s)?`),
+			variants:  regexp.MustCompile(`(?i)(?:` + strings.Join(alternatives, "|") + `)(e?s)?`),
+		})
+	}
+	return patterns
+}
+
+// phrasePattern quotes s, allowing any run of spaces between its words
+func phrasePattern(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	return strings.Join(words, `[ \t]+`)
+}
+
+// wholeWords returns the submatch indexes of re in s that do not start or
+// end inside a word, so "bus" does not match in "omnibus"
+func wholeWords(re *regexp.Regexp, s string) [][]int {
+	var kept [][]int
+	for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+		before, _ := utf8.DecodeLastRuneInString(s[:loc[0]])
+		first, _ := utf8.DecodeRuneInString(s[loc[0]:])
+		last, _ := utf8.DecodeLastRuneInString(s[:loc[1]])
+		after, _ := utf8.DecodeRuneInString(s[loc[1]:])
+		if (isWordRune(before) && isWordRune(first)) || (isWordRune(last) && isWordRune(after)) {
+			continue
+		}
+		kept = append(kept, loc)
+	}
+	return kept
+}
+
+// isWordRune reports whether r can be part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// findTerms returns the banned glossary variants in the prose of lines,
+// skipping code blocks, code spans, links targets and occurrences of the
+// preferred terms themselves
+func (v *Validator) findTerms(lines []string) []termMatch {
+	if len(v.glossary) == 0 {
+		return nil
+	}
+
+	var matches []termMatch
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || trimmed == "" {
+			continue
+		}
+
+		prose := proseMaskPattern.ReplaceAllStringFunc(line, func(s string) string {
+			return strings.Repeat(" ", len(s))
+		})
+
+		var preferred [][]int
+		for _, pattern := range v.glossary {
+			preferred = append(preferred, wholeWords(pattern.preferred, prose)...)
+		}
+
+		var found []termMatch
+		for _, pattern := range v.glossary {
+			for _, loc := range wholeWords(pattern.variants, prose) {
+				if overlapsAny(loc[0], loc[1], preferred) {
+					continue
+				}
+				text := line[loc[0]:loc[1]]
+				replacement := pattern.term
+				if loc[2] >= 0 {
+					replacement = pluralize(replacement)
+				}
+				found = append(found, termMatch{
+					line:        i,
+					start:       loc[0],
+					end:         loc[1],
+					found:       text,
+					replacement: matchCase(text, replacement),
+					unsafe:      unsafeReplacement(line, loc[0], text, replacement, trimmed),
+				})
+			}
+		}
+
+		// Keep the earliest, then longest, of overlapping variants
+		sort.Slice(found, func(a, b int) bool {
+			if found[a].start != found[b].start {
+				return found[a].start < found[b].start
+			}
+			return found[a].end > found[b].end
+		})
+		end := -1
+		for _, match := range found {
+			if match.start >= end {
+				matches = append(matches, match)
+				end = match.end
+			}
+		}
+	}
+	return matches
+}
+
+// unsafeReplacement explains why replacing a variant could change more
+// than the wording, or returns "" when the replacement is safe
+func unsafeReplacement(line string, start int, found, replacement, trimmed string) string {
+	if strings.HasPrefix(trimmed, "#") {
+		return "headings define link anchors, so rename it by hand"
+	}
+	if articlePattern.MatchString(line[:start]) && startsWithVowel(found) != startsWithVowel(replacement) {
+		return "the article before it must change too"
+	}
+	return ""
+}
+
+// overlapsAny reports whether [start, end) overlaps one of spans
+func overlapsAny(start, end int, spans [][]int) bool {
+	for _, span := range spans {
+		if start < span[1] && span[0] < end {
+			return true
+		}
+	}
+	return false
+}
+
+// pluralize adds an English plural suffix to the last word of term
+func pluralize(term string) string {
+	lower := strings.ToLower(term)
+	for _, suffix := range []string{"s", "x", "z", "ch", "sh"} {
+		if strings.HasSuffix(lower, suffix) {
+			return term + "es"
+		}
+	}
+	return term + "s"
+}
+
+// matchCase gives replacement the capitalization of found: upper case,
+// a capital first letter or as configured. Terms with capitals of their
+// own, such as product names, are always used as configured.
+func matchCase(found, replacement string) string {
+	if replacement != strings.ToLower(replacement) {
+		return replacement
+	}
+	if len(found) > 1 && found == strings.ToUpper(found) && found != strings.ToLower(found) {
+		return strings.ToUpper(replacement)
+	}
+	if r, _ := utf8.DecodeRuneInString(found); unicode.IsUpper(r) {
+		first, size := utf8.DecodeRuneInString(replacement)
+		return string(unicode.ToUpper(first)) + replacement[size:]
+	}
+	return replacement
+}
+
+// startsWithVowel approximates whether "an" rather than "a" precedes s
+func startsWithVowel(s string) bool {
+	return s != "" && strings.ContainsRune("aeiouAEIOU", rune(s[0]))
+}
+
+// validateTerminology flags banned glossary variants with the preferred
+// term as the replacement
+func (v *Validator) validateTerminology(filename string, lines []string, result *ValidationResult) {
+	for _, match := range v.findTerms(lines) {
+		issue := Issue{
+			Rule:    RuleTerminology,
+			File:    filename,
+			Line:    match.line + 1,
+			Column:  match.start + 1,
+			Level:   "warning",
+			Message: fmt.Sprintf("Use %q instead of %q", match.replacement, match.found),
+			Fixable: match.unsafe == "",
+		}
+		if match.unsafe != "" {
+			issue.Suggestion = "Not fixed automatically: " + match.unsafe
+		}
+		result.Issues = append(result.Issues, issue)
+		result.WarningCount++
+	}
+}
+
+// fixTerminology replaces banned glossary variants with their preferred
+// term where that is safe, leaving front matter and lines where the rule is
+// disabled inline untouched
+func fixTerminology(v *Validator, content string) string {
+	lines := strings.Split(content, "\n")
+	prose := append([]string{}, lines...)
+	if _, _, blockLines, ok := frontmatter.Split(content); ok {
+		for i := 0; i < blockLines && i < len(prose); i++ {
+			prose[i] = ""
+		}
+	}
+
+	directives := parseDirectives(prose)
+	matches := v.findTerms(prose)
+	// Replace from the end so earlier offsets stay valid
+	for i := len(matches) - 1; i >= 0; i-- {
+		match := matches[i]
+		if match.unsafe != "" || directives.disabled(RuleTerminology, match.line+1) {
+			continue
+		}
+		line := lines[match.line]
+		lines[match.line] = line[:match.start] + match.replacement + line[match.end:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/adrref"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+	"github.com/euforicio/adr-demo/internal/gitmeta"
+)
+
+// validateTransitions compares the status of each selected ADR with its
+// status at the base git revision and reports moves that status_transitions
+// does not allow. ADRs that did not exist at the base revision may start in
+// any status. Nothing is checked outside a git repository, and an empty
+// BaseRef is skipped quietly when HEAD has no commits yet.
+func (v *Validator) validateTransitions(files []adrfs.File, result *ValidationResult) error {
+	if len(v.config.Project.StatusTransitions) == 0 || !v.RuleEnabled(RuleStatusTransition) {
+		return nil
+	}
+	repo, err := gitmeta.Open(".")
+	if errors.Is(err, gitmeta.ErrNotRepository) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ref := v.config.BaseRef
+	if ref == "" {
+		ref = "HEAD"
+	}
+	base := repo.MergeBase(ref)
+	if !repo.HasRevision(base) {
+		if v.config.BaseRef == "" {
+			return nil
+		}
+		return fmt.Errorf("unknown git revision %s", ref)
+	}
+
+	for _, file := range files {
+		if !v.selected(file) {
+			continue
+		}
+		previous, existed, err := repo.FileAt(base, file.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s at %s: %w", file.RelPath(), ref, err)
+		}
+		if !existed {
+			continue
+		}
+
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.RelPath(), err)
+		}
+		lines := blankFrontMatter(string(data))
+		current, line := contentStatus(string(data))
+
+		from, _ := contentStatus(previous)
+		from, to := adrref.StatusWord(from), adrref.StatusWord(current)
+		if from == "" || to == "" || strings.EqualFold(from, to) || v.config.Project.CanTransition(from, to) {
+			continue
+		}
+
+		next, _ := v.config.Project.NextStatuses(from)
+		allowed := "none"
+		if len(next) > 0 {
+			allowed = strings.Join(next, ", ")
+		}
+		fileResult := &ValidationResult{}
+		fileResult.Issues = append(fileResult.Issues, Issue{
+			Rule:       RuleStatusTransition,
+			File:       file.RelPath(),
+			Line:       line,
+			Level:      "error",
+			Message:    fmt.Sprintf("Status changed from %s to %s since %s, which status_transitions does not allow", from, to, ref),
+			Suggestion: fmt.Sprintf("Allowed from %s: %s", from, allowed),
+		})
+		fileResult.ErrorCount++
+		v.applyRules(lines, fileResult)
+		result.merge(fileResult)
+	}
+	return nil
+}
+
+// contentStatus returns the status an ADR declares and the line declaring it
+func contentStatus(content string) (string, int) {
+	// Front matter parse errors are reported by the front-matter rule
+	fm, _, _ := frontmatter.Parse(content)
+	return documentStatus(fm, blankFrontMatter(content))
+}
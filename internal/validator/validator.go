@@ -3,17 +3,37 @@ package validator
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
-	"strconv"
+	"sort"
 	"strings"
+
+	"github.com/euforicio/adr-demo/internal/adrformat"
+	"github.com/euforicio/adr-demo/internal/adrfs"
+	"github.com/euforicio/adr-demo/internal/config"
+	"github.com/euforicio/adr-demo/internal/frontmatter"
+	"github.com/euforicio/adr-demo/internal/mermaid"
 )
 
 // Config holds the validator configuration
 type Config struct {
 	Strict  bool
 	Fix     bool
+	DryRun  bool // Preview fixes as a unified diff instead of writing them
 	Verbose bool
+
+	// Project is the loaded adr-config.yaml (defaults are used when nil)
+	Project *config.Config
+
+	// Paths limits per-file rules to these ADR files or directories. Rules
+	// across the log, such as numbering and supersession links, still run
+	// against every ADR. nil checks every file; an empty list checks none.
+	Paths []string
+
+	// BaseRef is the git revision status changes are checked against for
+	// the status-transition rule (HEAD when empty)
+	BaseRef string
 }
 
 // ValidationResult holds the validation results
@@ -24,25 +44,81 @@ type ValidationResult struct {
 	ErrorCount   int
 	WarningCount int
 	FixCount     int
+	Changes      []FileChange // Files rewritten (or, in dry-run mode, that would be)
+
+	// BaselineCount is the number of known issues removed by a baseline
+	BaselineCount int
 }
 
 // Issue represents a validation issue
 type Issue struct {
+	Rule    string // Stable rule identifier, e.g. "required-sections"
 	File    string
 	Line    int
+	Column  int
 	Level   string // "error" or "warning"
 	Message string
+	Fixable bool // A safe automatic fix exists for this issue
+
+	// Suggestion describes a manual fix, such as a rename or a renumber
+	Suggestion string
+}
+
+// FileChange describes a fix applied to a single file
+type FileChange struct {
+	File string
+	Diff string // Unified diff of the change
 }
 
+// Rule identifiers reported on each issue
+const (
+	RuleFilenameFormat     = "filename-format"
+	RuleNumbering          = "sequential-numbering"
+	RuleDuplicateNumber    = "duplicate-number"
+	RuleSlugTitle          = "slug-title"
+	RuleFrontMatter        = "front-matter"
+	RuleAllowedStatus      = "allowed-status"
+	RuleAllowedCategory    = "allowed-category"
+	RuleRequiredSections   = "required-sections"
+	RuleHeadingHierarchy   = "heading-hierarchy"
+	RuleMermaidFence       = "mermaid-fence"
+	RuleMermaidSyntax      = "mermaid-syntax"
+	RuleInternalLinks      = "internal-links"
+	RuleLineLength         = "line-length"
+	RuleTrailingWhitespace = "trailing-whitespace"
+	RulePlaceholders       = "placeholder-text"
+	RuleTerminology        = "terminology"
+
+	// Rules across the whole log
+	RuleSupersededTarget     = "superseded-target"
+	RuleSupersedesBacklink   = "supersedes-backlink"
+	RuleDeprecationReason    = "deprecation-reason"
+	RuleDeprecatedDependency = "deprecated-dependency"
+	RuleStatusTransition     = "status-transition"
+)
+
 // Validator validates ADR files
 type Validator struct {
-	config *Config
+	config  *Config
+	files   []adrfs.File               // Every ADR found by ValidateAll
+	only    map[string]bool            // Absolute paths selected by Config.Paths, nil for all
+	anchors map[string]map[string]bool // Heading IDs of linked files, by path
+
+	fingerprints map[string]map[string]bool // Template boilerplate lines, by format
+	glossary     []glossaryPattern          // Compiled glossary of the project
 }
 
 // New creates a new validator
-func New(config *Config) *Validator {
+func New(cfg *Config) *Validator {
+	if cfg.Project == nil {
+		cfg.Project = config.DefaultConfig()
+	}
 	return &Validator{
-		config: config,
+		config:  cfg,
+		anchors: make(map[string]map[string]bool),
+
+		fingerprints: make(map[string]map[string]bool),
+		glossary:     compileGlossary(cfg.Project.Glossary),
 	}
 }
 
@@ -51,57 +127,129 @@ func (v *Validator) ValidateAll() (*ValidationResult, error) {
 	result := &ValidationResult{
 		Issues: make([]Issue, 0),
 	}
-
-	// Find all ADR files
-	adrDir := "adr"
-	entries, err := os.ReadDir(adrDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read ADR directory: %w", err)
+	if err := CheckRules(v.config.Project); err != nil {
+		return nil, err
 	}
 
-	adrFiles := make([]string, 0)
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
-			continue
-		}
+	// Issues about the set of files rather than a single file's content
+	global := &ValidationResult{}
 
-		// Skip template file
-		if entry.Name() == "template.md" {
-			continue
-		}
+	// Find all ADR files, including category folders
+	adrDir := v.config.Project.ADRDirectory
+	files, err := adrfs.Discover(adrDir, v.config.Project.AllowedCategories)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.selectPaths(files); err != nil {
+		return nil, err
+	}
 
+	adrFiles := make([]adrfs.File, 0, len(files))
+	for _, file := range files {
 		// Check filename format
-		if v.isValidADRFilename(entry.Name()) {
-			adrFiles = append(adrFiles, entry.Name())
-		} else {
-			result.Issues = append(result.Issues, Issue{
-				File:    entry.Name(),
+		if v.isValidADRFilename(file.Name) {
+			adrFiles = append(adrFiles, file)
+		} else if v.selected(file) {
+			global.Issues = append(global.Issues, Issue{
+				Rule:    RuleFilenameFormat,
+				File:    file.RelPath(),
 				Line:    0,
 				Level:   "error",
 				Message: "Invalid ADR filename format. Expected: NNNN-kebab-case-title.md",
 			})
-			result.ErrorCount++
+			global.ErrorCount++
 		}
 	}
 
-	result.FileCount = len(adrFiles)
+	v.files = adrFiles
 
-	// Validate sequential numbering
-	if err := v.validateSequentialNumbering(adrFiles, result); err != nil {
+	// Validate sequential numbering across all folders
+	if err := v.validateSequentialNumbering(adrFiles, global); err != nil {
 		return nil, err
 	}
+	v.applyRules(nil, global)
+	result.merge(global)
 
-	// Validate each file
-	for _, filename := range adrFiles {
-		filePath := filepath.Join(adrDir, filename)
-		if err := v.validateFile(filePath, result); err != nil {
-			return nil, fmt.Errorf("failed to validate %s: %w", filename, err)
+	// Validate each selected file
+	for _, file := range adrFiles {
+		if !v.selected(file) {
+			continue
+		}
+		result.FileCount++
+		if err := v.validateFile(file, result); err != nil {
+			return nil, fmt.Errorf("failed to validate %s: %w", file.RelPath(), err)
 		}
 	}
 
+	// Validate relationships between ADRs, after fixes have been applied
+	if err := v.validateConsistency(adrFiles, result); err != nil {
+		return nil, err
+	}
+
+	// Status changes since the base revision follow status_transitions
+	if err := v.validateTransitions(adrFiles, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
+// selectPaths resolves Config.Paths against the discovered files. A path
+// may name an ADR or a directory of ADRs; paths that exist but are not ADRs,
+// such as other changed files, select nothing.
+func (v *Validator) selectPaths(files []adrfs.File) error {
+	v.only = nil
+	if v.config.Paths == nil {
+		return nil
+	}
+
+	v.only = make(map[string]bool)
+	for _, p := range v.config.Paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return fmt.Errorf("cannot validate %s: %w", p, err)
+		}
+		target, err := filepath.Abs(p)
+		if err != nil {
+			return fmt.Errorf("cannot validate %s: %w", p, err)
+		}
+
+		for _, file := range files {
+			abs, err := filepath.Abs(file.Path)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %s: %w", file.Path, err)
+			}
+			if abs == target {
+				v.only[abs] = true
+			} else if info.IsDir() {
+				if rel, err := filepath.Rel(target, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					v.only[abs] = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// selected reports whether per-file rules run for file
+func (v *Validator) selected(file adrfs.File) bool {
+	if v.only == nil {
+		return true
+	}
+	abs, err := filepath.Abs(file.Path)
+	return err == nil && v.only[abs]
+}
+
+// adrByNumber returns the ADR file with the given four-digit number
+func (v *Validator) adrByNumber(number string) (adrfs.File, bool) {
+	for _, file := range v.files {
+		if strings.HasPrefix(file.Name, number+"-") {
+			return file, true
+		}
+	}
+	return adrfs.File{}, false
+}
+
 // HasErrors returns true if there are validation errors
 func (r *ValidationResult) HasErrors() bool {
 	return r.ErrorCount > 0
@@ -114,36 +262,67 @@ func (v *Validator) isValidADRFilename(filename string) bool {
 	return matched
 }
 
-// validateSequentialNumbering ensures ADR numbers are sequential
-func (v *Validator) validateSequentialNumbering(filenames []string, result *ValidationResult) error {
+// validateSequentialNumbering ensures ADR numbers are unique and sequential.
+// Each duplicate comes with a suggested renumber to the next free number.
+func (v *Validator) validateSequentialNumbering(files []adrfs.File, result *ValidationResult) error {
+	byNumber := make(map[int][]adrfs.File)
 	numbers := make([]int, 0)
 
-	for _, filename := range filenames {
-		if len(filename) >= 4 {
-			numStr := filename[:4]
-			num, err := strconv.Atoi(numStr)
-			if err != nil {
-				result.Issues = append(result.Issues, Issue{
-					File:    filename,
-					Line:    0,
-					Level:   "error",
-					Message: "Invalid ADR number format",
-				})
-				result.ErrorCount++
-				continue
-			}
+	for _, file := range files {
+		num := file.Number()
+		if num < 0 {
+			result.Issues = append(result.Issues, Issue{
+				Rule:    RuleNumbering,
+				File:    file.RelPath(),
+				Line:    0,
+				Level:   "error",
+				Message: "Invalid ADR number format",
+			})
+			result.ErrorCount++
+			continue
+		}
+		if len(byNumber[num]) == 0 {
 			numbers = append(numbers, num)
 		}
+		byNumber[num] = append(byNumber[num], file)
+	}
+	sort.Ints(numbers)
+
+	// Check for duplicates; the oldest file keeps the number
+	next := 1
+	if len(numbers) > 0 {
+		next = numbers[len(numbers)-1] + 1
+	}
+	for _, num := range numbers {
+		group := byNumber[num]
+		if len(group) < 2 {
+			continue
+		}
+		adrfs.SortByAge(group)
+		for _, file := range group[1:] {
+			result.Issues = append(result.Issues, Issue{
+				Rule:       RuleDuplicateNumber,
+				File:       file.RelPath(),
+				Line:       0,
+				Level:      "error",
+				Message:    fmt.Sprintf("Duplicate ADR number %04d, also used by %s", num, group[0].RelPath()),
+				Suggestion: fmt.Sprintf("Renumber to %04d, rewriting links to it: adr-gen renumber %s %d", next, file.Path, next),
+			})
+			result.ErrorCount++
+			next++
+		}
 	}
 
-	// Check for gaps in numbering
+	// Check for gaps in numbering, reported on the file after the gap
 	for i := 0; i < len(numbers)-1; i++ {
 		if numbers[i+1] != numbers[i]+1 {
 			result.Issues = append(result.Issues, Issue{
-				File:    fmt.Sprintf("ADR-%04d", numbers[i+1]),
-				Line:    0,
-				Level:   "error",
-				Message: fmt.Sprintf("Gap in ADR numbering: %04d follows %04d", numbers[i+1], numbers[i]),
+				Rule:       RuleNumbering,
+				File:       byNumber[numbers[i+1]][0].RelPath(),
+				Line:       0,
+				Level:      "error",
+				Message:    fmt.Sprintf("Gap in ADR numbering: %04d follows %04d", numbers[i+1], numbers[i]),
+				Suggestion: "Close the gaps, rewriting links and keeping old URLs as redirects: adr-gen renumber --compact",
 			})
 			result.ErrorCount++
 		}
@@ -152,64 +331,343 @@ func (v *Validator) validateSequentialNumbering(filenames []string, result *Vali
 	return nil
 }
 
-// validateFile validates a single ADR file
-func (v *Validator) validateFile(filePath string, result *ValidationResult) error {
+// validateFile validates a single ADR file, applying safe fixes when enabled
+func (v *Validator) validateFile(file adrfs.File, result *ValidationResult) error {
+	filePath := file.Path
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return err
 	}
 
-	filename := filepath.Base(filePath)
-	lines := strings.Split(string(content), "\n")
+	filename := file.RelPath()
+	fileResult := v.checkContent(filename, string(content))
+
+	if v.config.Fix && fileResult.hasFixable() {
+		fixed, count := v.applyFixes(string(content), fileResult.Issues)
+		if fixed != string(content) {
+			change := FileChange{
+				File: filePath,
+				Diff: unifiedDiff(filePath, string(content), fixed),
+			}
+
+			// A dry run reports the file as it is on disk
+			if !v.config.DryRun {
+				if err := adrfs.WriteFileAtomic(filePath, []byte(fixed)); err != nil {
+					return fmt.Errorf("failed to write fixes: %w", err)
+				}
+				fileResult = v.checkContent(filename, fixed)
+			}
+			fileResult.FixCount = count
+			fileResult.Changes = append(fileResult.Changes, change)
+		}
+	}
+
+	result.merge(fileResult)
+	return nil
+}
+
+// checkContent runs all per-file checks against content without side effects
+func (v *Validator) checkContent(filename, content string) *ValidationResult {
+	result := &ValidationResult{}
+	lines := strings.Split(content, "\n")
+
+	// Check front matter and hide it from the markdown checks
+	fm, lines := v.validateFrontMatter(filename, content, lines, result)
+
+	// Check status and category against the configured values
+	v.validateAllowedValues(filename, lines, fm, result)
+
+	// Check the category folder, if any, against the declared category
+	v.validateCategoryFolder(filename, lines, fm, result)
 
 	// Check for required sections
-	v.validateRequiredSections(filename, lines, result)
+	v.validateRequiredSections(filename, lines, fm, result)
+
+	// Check for template boilerplate that was never filled in
+	v.validatePlaceholders(filename, lines, fm, result)
+
+	// Check wording against the glossary
+	v.validateTerminology(filename, lines, result)
 
 	// Check heading hierarchy
 	v.validateHeadingHierarchy(filename, lines, result)
 
+	// Check the filename slug against the title
+	v.validateSlug(filename, lines, result)
+
+	// Check internal links, anchors and images
+	v.validateLinks(filename, lines, result)
+
 	// Check for Mermaid diagrams
 	diagramCount := v.validateMermaidDiagrams(filename, lines, result)
 	result.DiagramCount += diagramCount
 
-	// Strict mode checks
-	if v.config.Strict {
-		v.validateStrictRules(filename, lines, result)
+	// Style checks, enabled by --strict or the rules configuration
+	v.validateStyleRules(filename, lines, result)
+
+	// Apply the rules configuration and inline adr-gen-disable comments
+	v.applyRules(lines, result)
+
+	return result
+}
+
+// merge folds the results of a single file into r
+func (r *ValidationResult) merge(other *ValidationResult) {
+	r.Issues = append(r.Issues, other.Issues...)
+	r.DiagramCount += other.DiagramCount
+	r.ErrorCount += other.ErrorCount
+	r.WarningCount += other.WarningCount
+	r.FixCount += other.FixCount
+	r.Changes = append(r.Changes, other.Changes...)
+}
+
+// hasFixable returns true if any issue has an automatic fix
+func (r *ValidationResult) hasFixable() bool {
+	for _, issue := range r.Issues {
+		if issue.Fixable {
+			return true
+		}
 	}
+	return false
+}
 
-	return nil
+// validateFrontMatter checks the YAML front matter block, if present, and returns
+// it along with the lines with the block blanked out so line numbers stay accurate
+func (v *Validator) validateFrontMatter(filename, content string, lines []string, result *ValidationResult) (*frontmatter.FrontMatter, []string) {
+	_, _, blockLines, ok := frontmatter.Split(content)
+	if !ok {
+		return nil, lines
+	}
+
+	fm, _, err := frontmatter.Parse(content)
+	if err != nil {
+		result.Issues = append(result.Issues, Issue{
+			Rule:    RuleFrontMatter,
+			File:    filename,
+			Line:    1,
+			Level:   "error",
+			Message: err.Error(),
+		})
+		result.ErrorCount++
+	} else {
+		if fm.Date != "" {
+			if _, ok := fm.ParsedDate(); !ok {
+				result.Issues = append(result.Issues, Issue{
+					Rule:    RuleFrontMatter,
+					File:    filename,
+					Line:    1,
+					Level:   "error",
+					Message: fmt.Sprintf("Invalid front matter date %q. Expected: YYYY-MM-DD", fm.Date),
+				})
+				result.ErrorCount++
+			}
+		}
+		if fm.Format != "" {
+			if _, err := adrformat.Lookup(fm.Format, v.config.Project); err != nil {
+				result.Issues = append(result.Issues, Issue{
+					Rule:    RuleFrontMatter,
+					File:    filename,
+					Line:    1,
+					Level:   "error",
+					Message: err.Error(),
+				})
+				result.ErrorCount++
+			}
+		}
+	}
+
+	masked := make([]string, len(lines))
+	copy(masked, lines)
+	for i := 0; i < blockLines && i < len(masked); i++ {
+		masked[i] = ""
+	}
+	return fm, masked
 }
 
-// validateRequiredSections checks for required ADR sections
-func (v *Validator) validateRequiredSections(filename string, lines []string, result *ValidationResult) {
-	requiredSections := []string{"Status", "Context", "Decision", "Consequences"}
-	foundSections := make(map[string]bool)
+// validateAllowedValues checks status and category values against the
+// allowed lists in the project configuration
+func (v *Validator) validateAllowedValues(filename string, lines []string, fm *frontmatter.FrontMatter, result *ValidationResult) {
+	project := v.config.Project
+
+	if fm != nil && fm.Status != "" {
+		v.checkAllowed(filename, 1, RuleAllowedStatus, "status", fm.Status, project.AllowedStatuses, result)
+	}
+	if fm != nil && fm.Category != "" {
+		v.checkAllowed(filename, 1, RuleAllowedCategory, "category", fm.Category, project.AllowedCategories, result)
+	}
 
-	for _, line := range lines {
+	inStatus := false
+	inCategory := false
+	inCode := false
+	for lineNum, line := range lines {
 		trimmed := strings.TrimSpace(line)
+
+		// Skip fenced code blocks
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
 		if strings.HasPrefix(trimmed, "## ") {
-			sectionName := strings.TrimSpace(trimmed[3:])
-			for _, required := range requiredSections {
-				if strings.EqualFold(sectionName, required) {
-					foundSections[required] = true
-				}
+			heading := strings.TrimSpace(trimmed[3:])
+			inStatus = strings.EqualFold(heading, "Status")
+			inCategory = strings.EqualFold(heading, "Category")
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(trimmed), "category:") {
+			value := strings.TrimSpace(trimmed[len("category:"):])
+			if value != "" {
+				v.checkAllowed(filename, lineNum+1, RuleAllowedCategory, "category", value, project.AllowedCategories, result)
 			}
+			continue
+		}
+
+		if trimmed == "" {
+			continue
+		}
+
+		// Only the first non-empty line of the section holds the value
+		if inStatus {
+			v.checkAllowed(filename, lineNum+1, RuleAllowedStatus, "status", trimmed, project.AllowedStatuses, result)
+			inStatus = false
+		} else if inCategory {
+			v.checkAllowed(filename, lineNum+1, RuleAllowedCategory, "category", trimmed, project.AllowedCategories, result)
+			inCategory = false
 		}
 	}
+}
+
+// validateCategoryFolder checks that a category folder maps to an allowed
+// category and agrees with the category declared in the document
+func (v *Validator) validateCategoryFolder(filename string, lines []string, fm *frontmatter.FrontMatter, result *ValidationResult) {
+	folder := path.Dir(filename)
+	if folder == "." {
+		return
+	}
+
+	// Discovery only reads folders named after an allowed category
+	category, ok := v.config.Project.CategoryForFolder(folder)
+	if !ok {
+		return
+	}
+
+	declared, line := declaredCategory(lines)
+	if fm != nil && fm.Category != "" {
+		declared, line = fm.Category, 1
+	}
+	if declared != "" && declared != category {
+		result.Issues = append(result.Issues, Issue{
+			Rule:    RuleAllowedCategory,
+			File:    filename,
+			Line:    line,
+			Level:   "warning",
+			Message: fmt.Sprintf("Category %q does not match folder %q (%s)", declared, folder, category),
+		})
+		result.WarningCount++
+	}
+}
 
-	for _, section := range requiredSections {
-		if !foundSections[section] {
+// declaredCategory returns the category written in the document body and its line
+func declaredCategory(lines []string) (string, int) {
+	inSection := false
+	inCode := false
+	for lineNum, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") {
+			inSection = strings.EqualFold(strings.TrimSpace(trimmed[3:]), "Category")
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(trimmed), "category:") {
+			if value := strings.TrimSpace(trimmed[len("category:"):]); value != "" {
+				return value, lineNum + 1
+			}
+		}
+		if inSection {
+			return trimmed, lineNum + 1
+		}
+	}
+	return "", 0
+}
+
+// checkAllowed reports an error when value is not one of allowed
+func (v *Validator) checkAllowed(filename string, line int, rule, kind, value string, allowed []string, result *ValidationResult) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+
+	message := fmt.Sprintf("Unknown %s %q. Allowed: %s", kind, value, strings.Join(allowed, ", "))
+	canonical, caseOnly := canonicalValue(value, allowed)
+	if caseOnly {
+		message = fmt.Sprintf("Unknown %s %q. Did you mean %q?", kind, value, canonical)
+	}
+
+	result.Issues = append(result.Issues, Issue{
+		Rule:    rule,
+		File:    filename,
+		Line:    line,
+		Level:   "error",
+		Message: message,
+		Fixable: caseOnly,
+	})
+	result.ErrorCount++
+}
+
+// validateRequiredSections checks for the sections required by the ADR's format
+func (v *Validator) validateRequiredSections(filename string, lines []string, fm *frontmatter.FrontMatter, result *ValidationResult) {
+	format := v.formatFor(fm, strings.Join(lines, "\n"))
+	if format == nil {
+		return
+	}
+
+	foundSections := adrformat.Sections(strings.Join(lines, "\n"))
+	for _, section := range format.RequiredSections {
+		if !foundSections[strings.ToLower(section)] {
 			result.Issues = append(result.Issues, Issue{
+				Rule:    RuleRequiredSections,
 				File:    filename,
 				Line:    0,
 				Level:   "error",
-				Message: fmt.Sprintf("Missing required section: %s", section),
+				Message: fmt.Sprintf("Missing required section for %s format: %s", format.Name, section),
+				Fixable: true,
 			})
 			result.ErrorCount++
 		}
 	}
 }
 
+// formatFor returns the format an ADR is written in, or nil if it is unknown
+func (v *Validator) formatFor(fm *frontmatter.FrontMatter, body string) *adrformat.Format {
+	declared := ""
+	if fm != nil {
+		declared = fm.Format
+	}
+	format, err := adrformat.Lookup(adrformat.Detect(declared, body, v.config.Project), v.config.Project)
+	if err != nil {
+		return nil
+	}
+
+	// The rules configuration can replace the format's required sections
+	if sections := v.stringsParam(RuleRequiredSections, "sections"); len(sections) > 0 {
+		override := *format
+		override.RequiredSections = sections
+		return &override
+	}
+	return format
+}
+
 // validateHeadingHierarchy checks heading structure
 func (v *Validator) validateHeadingHierarchy(filename string, lines []string, result *ValidationResult) {
 	hasTitle := false
@@ -223,6 +681,7 @@ func (v *Validator) validateHeadingHierarchy(filename string, lines []string, re
 			title := strings.TrimSpace(trimmed[2:])
 			if title == "" {
 				result.Issues = append(result.Issues, Issue{
+					Rule:    RuleHeadingHierarchy,
 					File:    filename,
 					Line:    lineNum + 1,
 					Level:   "error",
@@ -235,6 +694,7 @@ func (v *Validator) validateHeadingHierarchy(filename string, lines []string, re
 		// Check for multiple H1 headings
 		if strings.HasPrefix(trimmed, "# ") && hasTitle && lineNum > 10 {
 			result.Issues = append(result.Issues, Issue{
+				Rule:    RuleHeadingHierarchy,
 				File:    filename,
 				Line:    lineNum + 1,
 				Level:   "warning",
@@ -246,6 +706,7 @@ func (v *Validator) validateHeadingHierarchy(filename string, lines []string, re
 
 	if !hasTitle {
 		result.Issues = append(result.Issues, Issue{
+			Rule:    RuleHeadingHierarchy,
 			File:    filename,
 			Line:    0,
 			Level:   "error",
@@ -255,6 +716,41 @@ func (v *Validator) validateHeadingHierarchy(filename string, lines []string, re
 	}
 }
 
+// validateSlug warns when the filename no longer matches the title, as
+// happens when a title is edited after the ADR was created
+func (v *Validator) validateSlug(filename string, lines []string, result *ValidationResult) {
+	name := path.Base(filename)
+	if !v.isValidADRFilename(name) {
+		return
+	}
+
+	for lineNum, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "# ") {
+			continue
+		}
+
+		expected := adrfs.Slugify(strings.TrimSpace(trimmed[2:]))
+		slug := strings.TrimSuffix(name[5:], ".md")
+		if expected == "" || expected == slug {
+			return
+		}
+		renamed := path.Join(path.Dir(filename), name[:5]+expected+".md")
+		adrDir := v.config.Project.ADRDirectory
+		result.Issues = append(result.Issues, Issue{
+			Rule:    RuleSlugTitle,
+			File:    filename,
+			Line:    lineNum + 1,
+			Level:   "warning",
+			Message: fmt.Sprintf("Filename slug %q does not match the title (expected %q)", slug, expected),
+			Suggestion: fmt.Sprintf("Rename: git mv %s %s",
+				filepath.Join(adrDir, filepath.FromSlash(filename)), filepath.Join(adrDir, filepath.FromSlash(renamed))),
+		})
+		result.WarningCount++
+		return
+	}
+}
+
 // validateMermaidDiagrams validates Mermaid diagram syntax
 func (v *Validator) validateMermaidDiagrams(filename string, lines []string, result *ValidationResult) int {
 	diagramCount := 0
@@ -270,16 +766,19 @@ func (v *Validator) validateMermaidDiagrams(filename string, lines []string, res
 			diagramCount++
 		} else if trimmed == "```" && inMermaid {
 			inMermaid = false
+			v.checkMermaidSyntax(filename, lines[mermaidStart:i], mermaidStart, result)
 		}
 	}
 
 	// Check for unclosed Mermaid blocks
 	if inMermaid {
 		result.Issues = append(result.Issues, Issue{
+			Rule:    RuleMermaidFence,
 			File:    filename,
 			Line:    mermaidStart,
 			Level:   "error",
 			Message: "Unclosed Mermaid diagram block",
+			Fixable: true,
 		})
 		result.ErrorCount++
 	}
@@ -287,27 +786,58 @@ func (v *Validator) validateMermaidDiagrams(filename string, lines []string, res
 	return diagramCount
 }
 
-// validateStrictRules applies strict validation rules
-func (v *Validator) validateStrictRules(filename string, lines []string, result *ValidationResult) {
+// checkMermaidSyntax reports syntax problems in a single diagram. offset is
+// the number of file lines before the diagram source.
+func (v *Validator) checkMermaidSyntax(filename string, diagram []string, offset int, result *ValidationResult) {
+	for _, problem := range mermaid.Check(strings.Join(diagram, "\n")) {
+		level := "error"
+		if problem.Warning {
+			level = "warning"
+		}
+		result.Issues = append(result.Issues, Issue{
+			Rule:    RuleMermaidSyntax,
+			File:    filename,
+			Line:    offset + problem.Line,
+			Column:  problem.Column,
+			Level:   level,
+			Message: "Mermaid: " + problem.Message,
+		})
+		if problem.Warning {
+			result.WarningCount++
+		} else {
+			result.ErrorCount++
+		}
+	}
+}
+
+// validateStyleRules checks line length and trailing whitespace
+func (v *Validator) validateStyleRules(filename string, lines []string, result *ValidationResult) {
+	maxLength := v.intParam(RuleLineLength, "max")
+	checkLength := v.RuleEnabled(RuleLineLength)
+	checkWhitespace := v.RuleEnabled(RuleTrailingWhitespace)
+
 	for lineNum, line := range lines {
 		// Check line length
-		if len(line) > 120 {
+		if checkLength && len(line) > maxLength {
 			result.Issues = append(result.Issues, Issue{
+				Rule:    RuleLineLength,
 				File:    filename,
 				Line:    lineNum + 1,
 				Level:   "warning",
-				Message: "Line exceeds 120 characters",
+				Message: fmt.Sprintf("Line exceeds %d characters", maxLength),
 			})
 			result.WarningCount++
 		}
 
 		// Check for trailing whitespace
-		if len(line) > 0 && (line[len(line)-1] == ' ' || line[len(line)-1] == '\t') {
+		if checkWhitespace && len(line) > 0 && (line[len(line)-1] == ' ' || line[len(line)-1] == '\t') {
 			result.Issues = append(result.Issues, Issue{
+				Rule:    RuleTrailingWhitespace,
 				File:    filename,
 				Line:    lineNum + 1,
 				Level:   "warning",
 				Message: "Trailing whitespace",
+				Fixable: true,
 			})
 			result.WarningCount++
 		}
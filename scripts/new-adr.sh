@@ -48,10 +48,15 @@ fi
 # Copy template and customize
 cp "adr/template.md" "$filename"
 
-# Replace placeholders in the new file
-sed -i.bak "s/# \[Title\]/# $title/" "$filename"
-sed -i.bak "s/\[Brief description of the decision\]/Brief description of the decision to $filename_title/" "$filename"
-sed -i.bak "s/\[Current date\]/$(date '+%Y-%m-%d')/" "$filename"
+# Replace template variables in the new file
+sed -i.bak \
+  -e "s/{{number}}/$next_number/g" \
+  -e "s/{{title}}/$title/g" \
+  -e "s/{{status}}/Proposed/g" \
+  -e "s/{{date}}/$(date '+%Y-%m-%d')/g" \
+  -e "s/{{category}}/General/g" \
+  -e "s/{{author}}/$(git config user.name || echo "$USER")/g" \
+  "$filename"
 
 # Clean up backup file
 rm -f "${filename}.bak"